@@ -19,6 +19,7 @@ type IConfigureManager interface {
 	GetMysqlDBConfig() MysqlDBConfig
 	GetCloudflareConfig() CloudflareConfig
 	GetImageRefConfig() ImageRef
+	GetImageCatalogConfig() ImageCatalogConfig
 	GetPublicNetworkIDConfig() PublicNetworkID
 	GetLanguageConfig() LanguageConfig
 	GetEndpointsConfig() APIEndpointsConfig
@@ -26,6 +27,14 @@ type IConfigureManager interface {
 	GetVkeAgentConfig() VkeAgentConfig
 	GetOpenstackRolesConfig() OpenStackRolesConfig
 	GetLogstashConfig() LogstashConfig
+	GetServiceAccountConfig() ServiceAccountConfig
+	GetAutoRepairConfig() AutoRepairConfig
+	GetScheduledScalingConfig() ScheduledScalingConfig
+	GetControlPlaneReconcileConfig() ControlPlaneReconcileConfig
+	GetLoadBalancerConfig() LoadBalancerConfig
+	GetDNSConfig() DNSConfig
+	GetDNSGarbageCollectionConfig() DNSGarbageCollectionConfig
+	GetClusterNetworkConfig() ClusterNetworkConfig
 }
 
 type configureManager struct {
@@ -33,6 +42,7 @@ type configureManager struct {
 	Mysql                MysqlDBConfig
 	APIEndpoints         APIEndpointsConfig
 	ImageRef             ImageRef
+	ImageCatalog         ImageCatalogConfig
 	PublicNetworkID      PublicNetworkID
 	Cloudflare           CloudflareConfig
 	Language             LanguageConfig
@@ -40,6 +50,14 @@ type configureManager struct {
 	VkeAgentConfig       VkeAgentConfig
 	OpenStackRolesConfig OpenStackRolesConfig
 	LogstashConfig       LogstashConfig
+	ServiceAccountConfig ServiceAccountConfig
+	AutoRepairConfig     AutoRepairConfig
+	ScheduledScaling     ScheduledScalingConfig
+	ControlPlane         ControlPlaneReconcileConfig
+	LoadBalancer         LoadBalancerConfig
+	DNS                  DNSConfig
+	DNSGarbageCollection DNSGarbageCollectionConfig
+	ClusterNetwork       ClusterNetworkConfig
 }
 
 func NewConfigureManager() IConfigureManager {
@@ -61,12 +79,21 @@ func NewConfigureManager() IConfigureManager {
 		Mysql:                loadMysqlDBConfig(),
 		Cloudflare:           loadCloudflareConfig(),
 		ImageRef:             loadImageRefConfig(),
+		ImageCatalog:         loadImageCatalogConfig(),
 		PublicNetworkID:      loadPublicNetworkIDConfig(),
 		LogstashConfig:       loadLogstashConfig(),
 		APIEndpoints:         loadAPIEndpointsConfig(),
 		OpenStackApiConfig:   loadOpenStackApiConfig(),
 		VkeAgentConfig:       loadVkeAgentConfig(),
 		OpenStackRolesConfig: loadOpenstackRolesConfig(),
+		ServiceAccountConfig: loadServiceAccountConfig(),
+		AutoRepairConfig:     loadAutoRepairConfig(),
+		ScheduledScaling:     loadScheduledScalingConfig(),
+		ControlPlane:         loadControlPlaneReconcileConfig(),
+		LoadBalancer:         loadLoadBalancerConfig(),
+		DNS:                  loadDNSConfig(),
+		DNSGarbageCollection: loadDNSGarbageCollectionConfig(),
+		ClusterNetwork:       loadClusterNetworkConfig(),
 	}
 
 	return GlobalConfig
@@ -105,6 +132,15 @@ func loadImageRefConfig() ImageRef {
 	}
 }
 
+func loadImageCatalogConfig() ImageCatalogConfig {
+	var images []ImageCatalogItem
+	_ = viper.UnmarshalKey("IMAGE_CATALOG", &images)
+
+	return ImageCatalogConfig{
+		Images: images,
+	}
+}
+
 func loadPublicNetworkIDConfig() PublicNetworkID {
 	return PublicNetworkID{
 		PublicNetworkID: viper.GetString("PUBLIC_NETWORK_ID"),
@@ -125,6 +161,8 @@ func loadAPIEndpointsConfig() APIEndpointsConfig {
 		IdentityEndpoint:     viper.GetString("IDENTITY_ENDPOINT"),
 		BlockStorageEndpoint: viper.GetString("BLOCK_STORAGE_ENDPOINT"),
 		EnvoyEndpoint:        viper.GetString("ENVOY_ENDPOINT"),
+		ImageEndpoint:        viper.GetString("IMAGE_ENDPOINT"),
+		DNSEndpoint:          viper.GetString("DNS_ENDPOINT"),
 	}
 }
 
@@ -143,9 +181,12 @@ func loadVkeAgentConfig() VkeAgentConfig {
 }
 
 func loadOpenstackRolesConfig() OpenStackRolesConfig {
+	viper.SetDefault("OPENSTACK_ADMIN_ROLE", "admin")
+
 	return OpenStackRolesConfig{
 		OpenstackLoadbalancerRole: viper.GetString("OPENSTACK_LOADBALANCER_ADMIN_ROLE"),
 		OpenstackMemberOrUserRole: viper.GetString("OPENSTACK_USER_OR_MEMBER_ROLE"),
+		OpenstackAdminRole:        viper.GetString("OPENSTACK_ADMIN_ROLE"),
 	}
 }
 
@@ -156,6 +197,129 @@ func loadLogstashConfig() LogstashConfig {
 	}
 }
 
+func loadServiceAccountConfig() ServiceAccountConfig {
+	return ServiceAccountConfig{
+		Username:   viper.GetString("SERVICE_ACCOUNT_USERNAME"),
+		Password:   viper.GetString("SERVICE_ACCOUNT_PASSWORD"),
+		DomainName: viper.GetString("SERVICE_ACCOUNT_DOMAIN_NAME"),
+	}
+}
+
+func loadAutoRepairConfig() AutoRepairConfig {
+	viper.SetDefault("AUTO_REPAIR_INTERVAL_SECONDS", 300)
+	viper.SetDefault("AUTO_REPAIR_NOT_READY_GRACE_MINUTES", 10)
+	viper.SetDefault("AUTO_REPAIR_MAX_REPAIRS_PER_WINDOW", 1)
+	viper.SetDefault("AUTO_REPAIR_WINDOW_MINUTES", 60)
+
+	return AutoRepairConfig{
+		Enabled:              viper.GetBool("AUTO_REPAIR_ENABLED"),
+		IntervalSeconds:      viper.GetInt("AUTO_REPAIR_INTERVAL_SECONDS"),
+		NotReadyGraceMinutes: viper.GetInt("AUTO_REPAIR_NOT_READY_GRACE_MINUTES"),
+		MaxRepairsPerWindow:  viper.GetInt("AUTO_REPAIR_MAX_REPAIRS_PER_WINDOW"),
+		RepairWindowMinutes:  viper.GetInt("AUTO_REPAIR_WINDOW_MINUTES"),
+	}
+}
+
+func loadScheduledScalingConfig() ScheduledScalingConfig {
+	viper.SetDefault("SCHEDULED_SCALING_INTERVAL_SECONDS", 60)
+
+	return ScheduledScalingConfig{
+		Enabled:         viper.GetBool("SCHEDULED_SCALING_ENABLED"),
+		IntervalSeconds: viper.GetInt("SCHEDULED_SCALING_INTERVAL_SECONDS"),
+	}
+}
+
+func loadControlPlaneReconcileConfig() ControlPlaneReconcileConfig {
+	viper.SetDefault("CONTROL_PLANE_RECONCILE_INTERVAL_SECONDS", 600)
+
+	return ControlPlaneReconcileConfig{
+		Enabled:         viper.GetBool("CONTROL_PLANE_RECONCILE_ENABLED"),
+		IntervalSeconds: viper.GetInt("CONTROL_PLANE_RECONCILE_INTERVAL_SECONDS"),
+	}
+}
+
+func loadLoadBalancerConfig() LoadBalancerConfig {
+	viper.SetDefault("LB_HEALTH_MONITOR_TYPE", "TCP")
+	viper.SetDefault("LB_HEALTH_MONITOR_DELAY", 10)
+	viper.SetDefault("LB_HEALTH_MONITOR_TIMEOUT", 10)
+	viper.SetDefault("LB_HEALTH_MONITOR_MAX_RETRIES", 10)
+	viper.SetDefault("LB_HEALTH_MONITOR_HTTP_PATH", "/readyz")
+	viper.SetDefault("LB_HEALTH_MONITOR_EXPECTED_CODES", "200")
+	viper.SetDefault("LB_ALGORITHM", "SOURCE_IP_PORT")
+
+	return LoadBalancerConfig{
+		HealthMonitorType:  viper.GetString("LB_HEALTH_MONITOR_TYPE"),
+		Delay:              viper.GetInt("LB_HEALTH_MONITOR_DELAY"),
+		Timeout:            viper.GetInt("LB_HEALTH_MONITOR_TIMEOUT"),
+		MaxRetries:         viper.GetInt("LB_HEALTH_MONITOR_MAX_RETRIES"),
+		HTTPPath:           viper.GetString("LB_HEALTH_MONITOR_HTTP_PATH"),
+		ExpectedCodes:      viper.GetString("LB_HEALTH_MONITOR_EXPECTED_CODES"),
+		Algorithm:          viper.GetString("LB_ALGORITHM"),
+		SessionPersistence: viper.GetString("LB_SESSION_PERSISTENCE"),
+	}
+}
+
+// loadDNSConfig falls back to CLOUDFLARE_DOMAIN so existing deployments keep
+// their cluster endpoints when DNS_DOMAIN is not set.
+func loadDNSConfig() DNSConfig {
+	viper.SetDefault("DNS_PROVIDER", "cloudflare")
+	viper.SetDefault("DNS_TTL", 3600)
+	viper.SetDefault("RFC2136_TSIG_ALGORITHM", "hmac-sha256")
+
+	domain := viper.GetString("DNS_DOMAIN")
+	if domain == "" {
+		domain = viper.GetString("CLOUDFLARE_DOMAIN")
+	}
+	allowedZones := viper.GetStringSlice("DNS_ALLOWED_ZONES")
+	if len(allowedZones) == 0 && domain != "" {
+		allowedZones = []string{domain}
+	}
+
+	return DNSConfig{
+		Provider:             viper.GetString("DNS_PROVIDER"),
+		Domain:               domain,
+		TTL:                  viper.GetInt("DNS_TTL"),
+		AllowedZones:         allowedZones,
+		DesignateZoneID:      viper.GetString("DESIGNATE_ZONE_ID"),
+		DesignateProjectID:   viper.GetString("DESIGNATE_PROJECT_ID"),
+		RFC2136Server:        viper.GetString("RFC2136_SERVER"),
+		RFC2136Zone:          viper.GetString("RFC2136_ZONE"),
+		RFC2136TSIGKeyName:   viper.GetString("RFC2136_TSIG_KEY_NAME"),
+		RFC2136TSIGSecret:    viper.GetString("RFC2136_TSIG_SECRET"),
+		RFC2136TSIGAlgorithm: viper.GetString("RFC2136_TSIG_ALGORITHM"),
+	}
+}
+
+func loadDNSGarbageCollectionConfig() DNSGarbageCollectionConfig {
+	viper.SetDefault("DNS_GC_INTERVAL_SECONDS", 3600)
+
+	return DNSGarbageCollectionConfig{
+		Enabled:         viper.GetBool("DNS_GC_ENABLED"),
+		IntervalSeconds: viper.GetInt("DNS_GC_INTERVAL_SECONDS"),
+		DeleteOrphans:   viper.GetBool("DNS_GC_DELETE_ORPHANS"),
+	}
+}
+
+// loadClusterNetworkConfig holds the defaults of the networks vke creates for
+// clusters which do not bring their own subnets and of the pod and service
+// networks of dual-stack and IPv6 clusters.
+func loadClusterNetworkConfig() ClusterNetworkConfig {
+	viper.SetDefault("CLUSTER_NETWORK_CIDR", "10.0.0.0/24")
+	viper.SetDefault("CLUSTER_POD_CIDR", "10.42.0.0/16")
+	viper.SetDefault("CLUSTER_SERVICE_CIDR", "10.43.0.0/16")
+	viper.SetDefault("CLUSTER_IPV6_POD_CIDR", "fd00:42::/56")
+	viper.SetDefault("CLUSTER_IPV6_SERVICE_CIDR", "fd00:43::/112")
+
+	return ClusterNetworkConfig{
+		CIDR:            viper.GetString("CLUSTER_NETWORK_CIDR"),
+		DNSNameservers:  viper.GetStringSlice("CLUSTER_NETWORK_DNS_NAMESERVERS"),
+		PodCIDR:         viper.GetString("CLUSTER_POD_CIDR"),
+		ServiceCIDR:     viper.GetString("CLUSTER_SERVICE_CIDR"),
+		IPv6PodCIDR:     viper.GetString("CLUSTER_IPV6_POD_CIDR"),
+		IPv6ServiceCIDR: viper.GetString("CLUSTER_IPV6_SERVICE_CIDR"),
+	}
+}
+
 func (c *configureManager) GetWebConfig() WebConfig {
 	return c.Web
 }
@@ -176,6 +340,10 @@ func (c *configureManager) GetImageRefConfig() ImageRef {
 	return c.ImageRef
 }
 
+func (c *configureManager) GetImageCatalogConfig() ImageCatalogConfig {
+	return c.ImageCatalog
+}
+
 func (c *configureManager) GetPublicNetworkIDConfig() PublicNetworkID {
 	return c.PublicNetworkID
 }
@@ -188,6 +356,8 @@ func (c *configureManager) GetEndpointsConfig() APIEndpointsConfig {
 		IdentityEndpoint:     viper.GetString("IDENTITY_ENDPOINT"),
 		BlockStorageEndpoint: viper.GetString("BLOCK_STORAGE_ENDPOINT"),
 		EnvoyEndpoint:        viper.GetString("ENVOY_ENDPOINT"),
+		ImageEndpoint:        viper.GetString("IMAGE_ENDPOINT"),
+		DNSEndpoint:          viper.GetString("DNS_ENDPOINT"),
 	}
 }
 
@@ -211,9 +381,42 @@ func (c *configureManager) GetOpenstackRolesConfig() OpenStackRolesConfig {
 	return OpenStackRolesConfig{
 		OpenstackLoadbalancerRole: viper.GetString("OPENSTACK_LOADBALANCER_ADMIN_ROLE"),
 		OpenstackMemberOrUserRole: viper.GetString("OPENSTACK_USER_OR_MEMBER_ROLE"),
+		OpenstackAdminRole:        viper.GetString("OPENSTACK_ADMIN_ROLE"),
 	}
 }
 
 func (c *configureManager) GetLogstashConfig() LogstashConfig {
 	return c.LogstashConfig
 }
+
+func (c *configureManager) GetServiceAccountConfig() ServiceAccountConfig {
+	return c.ServiceAccountConfig
+}
+
+func (c *configureManager) GetAutoRepairConfig() AutoRepairConfig {
+	return c.AutoRepairConfig
+}
+
+func (c *configureManager) GetScheduledScalingConfig() ScheduledScalingConfig {
+	return c.ScheduledScaling
+}
+
+func (c *configureManager) GetControlPlaneReconcileConfig() ControlPlaneReconcileConfig {
+	return c.ControlPlane
+}
+
+func (c *configureManager) GetLoadBalancerConfig() LoadBalancerConfig {
+	return c.LoadBalancer
+}
+
+func (c *configureManager) GetDNSConfig() DNSConfig {
+	return c.DNS
+}
+
+func (c *configureManager) GetDNSGarbageCollectionConfig() DNSGarbageCollectionConfig {
+	return c.DNSGarbageCollection
+}
+
+func (c *configureManager) GetClusterNetworkConfig() ClusterNetworkConfig {
+	return c.ClusterNetwork
+}
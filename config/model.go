@@ -37,6 +37,8 @@ type APIEndpointsConfig struct {
 	IdentityEndpoint     string
 	BlockStorageEndpoint string
 	EnvoyEndpoint        string
+	ImageEndpoint        string
+	DNSEndpoint          string
 }
 
 type CloudflareConfig struct {
@@ -49,6 +51,16 @@ type ImageRef struct {
 	ImageRef string
 }
 
+type ImageCatalogConfig struct {
+	Images []ImageCatalogItem
+}
+
+type ImageCatalogItem struct {
+	ImageRef  string `mapstructure:"imageRef"`
+	OSName    string `mapstructure:"osName"`
+	OSVersion string `mapstructure:"osVersion"`
+}
+
 type PublicNetworkID struct {
 	PublicNetworkID string
 }
@@ -65,9 +77,74 @@ type VkeAgentConfig struct {
 	CloudProviderVkeVersion  string
 }
 
+type ServiceAccountConfig struct {
+	Username   string
+	Password   string
+	DomainName string
+}
+
+type AutoRepairConfig struct {
+	Enabled              bool
+	IntervalSeconds      int
+	NotReadyGraceMinutes int
+	MaxRepairsPerWindow  int
+	RepairWindowMinutes  int
+}
+
+type ScheduledScalingConfig struct {
+	Enabled         bool
+	IntervalSeconds int
+}
+
+type ControlPlaneReconcileConfig struct {
+	Enabled         bool
+	IntervalSeconds int
+}
+
+type LoadBalancerConfig struct {
+	HealthMonitorType  string
+	Delay              int
+	Timeout            int
+	MaxRetries         int
+	HTTPPath           string
+	ExpectedCodes      string
+	Algorithm          string
+	SessionPersistence string
+}
+
+type DNSConfig struct {
+	Provider             string
+	Domain               string
+	TTL                  int
+	AllowedZones         []string
+	DesignateZoneID      string
+	DesignateProjectID   string
+	RFC2136Server        string
+	RFC2136Zone          string
+	RFC2136TSIGKeyName   string
+	RFC2136TSIGSecret    string
+	RFC2136TSIGAlgorithm string
+}
+
+type DNSGarbageCollectionConfig struct {
+	Enabled         bool
+	IntervalSeconds int
+	DeleteOrphans   bool
+}
+
+type ClusterNetworkConfig struct {
+	CIDR            string
+	DNSNameservers  []string
+	PodCIDR         string
+	ServiceCIDR     string
+	IPv6PodCIDR     string
+	IPv6ServiceCIDR string
+}
+
 type OpenStackRolesConfig struct {
 	OpenstackLoadbalancerRole string
 	OpenstackMemberOrUserRole string
+	OpenstackAdminRole        string
 }
 
 func (w WebConfig) IsProductionEnv() bool {
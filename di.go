@@ -1,6 +1,8 @@
 package goboilerplate
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 	"github.com/vmindtech/vke/internal/handler"
 	"github.com/vmindtech/vke/internal/repository"
@@ -21,16 +23,30 @@ func InitRoute(l *logrus.Logger, mysqlInstance mysqldb.IMysqlInstance) route.IRo
 	iNodeGroupsRepository := repository.NewNodeGroupsRepository(mysqlInstance)
 	iResourcesRepository := repository.NewResourcesRepository(mysqlInstance)
 	iErrorRepository := repository.NewErrorRepository(mysqlInstance)
-	iRepository := repository.NewRepository(mysqlInstance, iClusterRepository, iAuditRepository, iKubeConfigRepository, iNodeGroupsRepository, iResourcesRepository, iErrorRepository)
+	iNodeGroupSchedulesRepository := repository.NewNodeGroupSchedulesRepository(mysqlInstance)
+	iIngressLoadBalancersRepository := repository.NewIngressLoadBalancersRepository(mysqlInstance)
+	iRepository := repository.NewRepository(mysqlInstance, iClusterRepository, iAuditRepository, iKubeConfigRepository, iNodeGroupsRepository, iResourcesRepository, iErrorRepository, iNodeGroupSchedulesRepository, iIngressLoadBalancersRepository)
 
 	iIdentityService := service.NewIdentityService(l)
 	iNetworkService := service.NewNetworkService(l)
-	iCloudflareService := service.NewCloudflareService(l)
 	iLoadbalancerService := service.NewLoadbalancerService(l)
-	iComputeService := service.NewComputeService(l, iIdentityService, iRepository)
-	iNodeGroupsService := service.NewNodeGroupsService(l, iRepository, iIdentityService, iComputeService, iNetworkService)
-	iClusterService := service.NewClusterService(l, iCloudflareService, iLoadbalancerService, iNetworkService, iComputeService, iNodeGroupsService, iIdentityService, iRepository)
-	iAppService := service.NewAppService(l, iRepository, iClusterService, iComputeService, iNodeGroupsService)
+	iDNSService := service.NewDNSService(l, service.NewCloudflareDNSProvider(l), service.NewDesignateDNSProvider(l, iIdentityService), service.NewRFC2136DNSProvider(l))
+	iKubernetesService := service.NewKubernetesService(l, iRepository)
+	iComputeService := service.NewComputeService(l, iIdentityService, iKubernetesService, iRepository)
+	iImageService := service.NewImageService(l, iIdentityService)
+	iIngressService := service.NewIngressService(l, iRepository, iIdentityService, iLoadbalancerService, iNetworkService, iComputeService, iDNSService)
+	iNodeGroupsService := service.NewNodeGroupsService(l, iRepository, iIdentityService, iComputeService, iNetworkService, iImageService, iKubernetesService, iIngressService)
+	iClusterService := service.NewClusterService(l, iDNSService, iLoadbalancerService, iNetworkService, iComputeService, iNodeGroupsService, iIdentityService, iImageService, iKubernetesService, iIngressService, iRepository)
+	iAutoRepairService := service.NewAutoRepairService(l, iRepository, iIdentityService, iComputeService, iNodeGroupsService, iKubernetesService)
+	go iAutoRepairService.Start(context.Background())
+	iScheduledScalingService := service.NewScheduledScalingService(l, iRepository, iIdentityService, iNodeGroupsService)
+	go iScheduledScalingService.Start(context.Background())
+	iControlPlaneReconcilerService := service.NewControlPlaneReconcilerService(l, iRepository, iIdentityService, iClusterService)
+	go iControlPlaneReconcilerService.Start(context.Background())
+	iDNSGarbageCollectorService := service.NewDNSGarbageCollectorService(l, iRepository, iIdentityService, iDNSService)
+	go iDNSGarbageCollectorService.Start(context.Background())
+
+	iAppService := service.NewAppService(l, iRepository, iClusterService, iComputeService, iNodeGroupsService, iImageService, iIngressService, iDNSGarbageCollectorService)
 
 	iAppHandler := handler.NewAppHandler(iAppService)
 	iRoute := route.NewRoute(iAppHandler)
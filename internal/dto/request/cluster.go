@@ -3,18 +3,71 @@ package request
 import "time"
 
 type CreateClusterRequest struct {
-	ClusterName              string   `json:"clusterName" validate:"required,max=50"`
-	ProjectID                string   `json:"projectId" validate:"required"`
-	KubernetesVersion        string   `json:"kubernetesVersion" validate:"required,max=30"`
-	NodeKeyPairName          string   `json:"nodeKeyPairName" validate:"required,max=140"`
-	ClusterAPIAccess         string   `json:"clusterApiAccess" validate:"required,max=255"`
-	SubnetIDs                []string `json:"subnetIds" validate:"required"`
-	WorkerNodeGroupMinSize   int      `json:"workerNodeGroupMinSize" validate:"required,min=1"`
-	WorkerNodeGroupMaxSize   int      `json:"workerNodeGroupMaxSize" validate:"required,min=1"`
-	WorkerInstanceFlavorUUID string   `json:"workerInstanceFlavorUUID" validate:"required"`
-	MasterInstanceFlavorUUID string   `json:"masterInstanceFlavorUUID" validate:"required"`
-	WorkerDiskSizeGB         int      `json:"workerDiskSizeGB" validate:"required,min=20"`
-	AllowedCIDRS             []string `json:"allowedCIDRs" validate:"required"`
+	ClusterName              string             `json:"clusterName" validate:"required,max=50"`
+	ProjectID                string             `json:"projectId" validate:"required"`
+	KubernetesVersion        string             `json:"kubernetesVersion" validate:"required,max=30"`
+	NodeKeyPairName          string             `json:"nodeKeyPairName" validate:"required,max=140"`
+	ClusterAPIAccess         string             `json:"clusterApiAccess" validate:"required,max=255"`
+	SubnetIDs                []string           `json:"subnetIds" validate:"required_without=CreateNetwork"`
+	WorkerNodeGroupMinSize   int                `json:"workerNodeGroupMinSize" validate:"required,min=1"`
+	WorkerNodeGroupMaxSize   int                `json:"workerNodeGroupMaxSize" validate:"required,min=1"`
+	WorkerInstanceFlavorUUID string             `json:"workerInstanceFlavorUUID" validate:"required"`
+	MasterInstanceFlavorUUID string             `json:"masterInstanceFlavorUUID" validate:"required"`
+	WorkerDiskSizeGB         int                `json:"workerDiskSizeGB" validate:"required,min=20"`
+	AllowedCIDRS             []string           `json:"allowedCIDRs" validate:"required,dive,cidr"`
+	MasterServerGroupPolicy  string             `json:"masterServerGroupPolicy" validate:"omitempty,oneof=affinity anti-affinity soft-affinity soft-anti-affinity"`
+	WorkerServerGroupPolicy  string             `json:"workerServerGroupPolicy" validate:"omitempty,oneof=affinity anti-affinity soft-affinity soft-anti-affinity"`
+	MasterImageRef           string             `json:"masterImageRef" validate:"omitempty,max=36"`
+	WorkerImageRef           string             `json:"workerImageRef" validate:"omitempty,max=36"`
+	UserData                 UserDataExtensions `json:"userData"`
+	// NodeGroups are created after the default worker node group as part of
+	// the cluster creation.
+	NodeGroups []CreateNodeGroupRequest `json:"nodeGroups"`
+	// LoadBalancerSettings override the configured control plane load
+	// balancer settings.
+	LoadBalancerSettings *LoadBalancerSettings `json:"loadBalancerSettings"`
+	// LoadBalancerProvider and LoadBalancerFlavorID select the Octavia
+	// provider and flavor of every load balancer of the cluster.
+	LoadBalancerProvider string `json:"loadBalancerProvider" validate:"omitempty,max=64"`
+	LoadBalancerFlavorID string `json:"loadBalancerFlavorId" validate:"omitempty,max=36"`
+	// CustomDNSNames get a DNS record pointing at the API load balancer and
+	// are added to the API server certificate with ExtraTLSSANs. Names must
+	// be in one of the DNS_ALLOWED_ZONES.
+	CustomDNSNames []string `json:"customDnsNames" validate:"omitempty,max=10,dive,max=253"`
+	ExtraTLSSANs   []string `json:"extraTlsSans" validate:"omitempty,max=20,dive,max=253"`
+	// AppsWildcardDNS creates a *.apps.<cluster endpoint> record pointing at
+	// the address, or at the managed ingress load balancer once it is created
+	// when the address is empty.
+	AppsWildcardDNS *AppsWildcardDNS `json:"appsWildcardDns"`
+	// CreateNetwork lets vke create a network, a subnet and a router uplinked
+	// to the public network for the cluster instead of using SubnetIDs.
+	CreateNetwork *CreateNetwork `json:"createNetwork"`
+	// PodCIDR and ServiceCIDR override the pod and service networks, with one
+	// comma separated CIDR per IP version of the cluster subnets. They must not
+	// overlap each other, the cluster subnets or AllowedCIDRS.
+	PodCIDR     string `json:"podCIDR" validate:"omitempty,max=128"`
+	ServiceCIDR string `json:"serviceCIDR" validate:"omitempty,max=128"`
+	// ClusterDomain overrides the cluster DNS domain, cluster.local by default.
+	ClusterDomain string `json:"clusterDomain" validate:"omitempty,fqdn,max=253"`
+	// CNI selects the CNI plugin, canal by default. none deploys no CNI so
+	// one can be installed after the cluster is created.
+	CNI        string      `json:"cni" validate:"omitempty,oneof=canal calico cilium none"`
+	CNIOptions *CNIOptions `json:"cniOptions"`
+}
+
+type CNIOptions struct {
+	// KubeProxyReplacement replaces kube-proxy with cilium.
+	KubeProxyReplacement bool `json:"kubeProxyReplacement"`
+	// MTU overrides the MTU of the pod network, detected by the CNI when empty.
+	MTU int `json:"mtu" validate:"omitempty,min=1280,max=9000"`
+}
+
+type CreateNetwork struct {
+	CIDR string `json:"cidr" validate:"omitempty,cidrv4"`
+}
+
+type AppsWildcardDNS struct {
+	Address string `json:"address" validate:"omitempty,ip"`
 }
 
 type CreateKubeconfigRequest struct {
@@ -23,11 +23,12 @@ type SchedulerHints struct {
 
 type BlockDeviceMappingV2 struct {
 	BootIndex           int    `json:"boot_index"`
-	UUID                string `json:"uuid"`
+	UUID                string `json:"uuid,omitempty"`
 	SourceType          string `json:"source_type"`
 	DestinationType     string `json:"destination_type"`
 	DeleteOnTermination bool   `json:"delete_on_termination"`
 	VolumeSize          int    `json:"volume_size"`
+	VolumeType          string `json:"volume_type,omitempty"`
 }
 
 type Networks struct {
@@ -43,6 +44,7 @@ type CreateServerGroupRequest struct {
 }
 
 type ServerGroup struct {
-	Name   string `json:"name"`
-	Policy string `json:"policy"`
+	Name     string   `json:"name"`
+	Policy   string   `json:"policy,omitempty"`
+	Policies []string `json:"policies,omitempty"`
 }
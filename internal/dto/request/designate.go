@@ -0,0 +1,15 @@
+package request
+
+type CreateRecordSetRequest struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Records     []string `json:"records"`
+	TTL         int      `json:"ttl"`
+	Description string   `json:"description"`
+}
+
+type UpdateRecordSetRequest struct {
+	Records     []string `json:"records"`
+	TTL         int      `json:"ttl"`
+	Description string   `json:"description"`
+}
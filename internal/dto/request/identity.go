@@ -9,3 +9,39 @@ type ApplicationCredential struct {
 	Description string              `json:"description"`
 	Roles       []map[string]string `json:"roles"`
 }
+
+type CreateTokenRequest struct {
+	Auth TokenAuth `json:"auth"`
+}
+
+type TokenAuth struct {
+	Identity TokenIdentity `json:"identity"`
+	Scope    TokenScope    `json:"scope"`
+}
+
+type TokenIdentity struct {
+	Methods  []string      `json:"methods"`
+	Password TokenPassword `json:"password"`
+}
+
+type TokenPassword struct {
+	User TokenUser `json:"user"`
+}
+
+type TokenUser struct {
+	Name     string      `json:"name"`
+	Password string      `json:"password"`
+	Domain   TokenDomain `json:"domain"`
+}
+
+type TokenDomain struct {
+	Name string `json:"name"`
+}
+
+type TokenScope struct {
+	Project TokenProject `json:"project"`
+}
+
+type TokenProject struct {
+	ID string `json:"id"`
+}
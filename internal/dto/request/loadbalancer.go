@@ -10,6 +10,14 @@ type LoadBalancer struct {
 	AdminStateUp bool   `json:"admin_state_up"`
 	VIPSubnetID  string `json:"vip_subnet_id"`
 	Provider     string `json:"provider"`
+	FlavorID     string `json:"flavor_id,omitempty"`
+	// AdditionalVIPs adds an IPv6 VIP to the load balancers of dual-stack
+	// clusters.
+	AdditionalVIPs []AdditionalVIP `json:"additional_vips,omitempty"`
+}
+
+type AdditionalVIP struct {
+	SubnetID string `json:"subnet_id"`
 }
 
 type CreateListenerRequest struct {
@@ -34,6 +42,12 @@ type Pool struct {
 	AdminStateUp bool   `json:"admin_state_up"`
 	ListenerID   string `json:"listener_id"`
 	Name         string `json:"name"`
+
+	SessionPersistence *SessionPersistence `json:"session_persistence,omitempty"`
+}
+
+type SessionPersistence struct {
+	Type string `json:"type"`
 }
 
 type AddMemberRequest struct {
@@ -61,6 +75,9 @@ type HealthMonitorHTTP struct {
 	TimeOut        string `json:"timeout"`
 	Type           string `json:"type"`
 	MaxRetriesDown int    `json:"max_retries_down"`
+	HTTPMethod     string `json:"http_method,omitempty"`
+	URLPath        string `json:"url_path,omitempty"`
+	ExpectedCodes  string `json:"expected_codes,omitempty"`
 }
 
 type CreateHealthMonitorTCPRequest struct {
@@ -76,3 +93,28 @@ type HealthMonitorTCP struct {
 	Type           string `json:"type"`
 	MaxRetriesDown int    `json:"max_retries_down"`
 }
+
+type CreateIngressLoadBalancerRequest struct {
+	NodeGroupID string            `json:"nodeGroupId"`
+	Public      bool              `json:"public"`
+	Listeners   []IngressListener `json:"listeners"`
+}
+
+type IngressListener struct {
+	Protocol string `json:"protocol"`
+	Port     int    `json:"port"`
+	NodePort int    `json:"nodePort"`
+}
+
+// LoadBalancerSettings override the health monitor and pool settings of the
+// control plane load balancer, empty fields fall back to the configuration.
+type LoadBalancerSettings struct {
+	HealthMonitorType          string `json:"healthMonitorType"`
+	HealthMonitorDelay         int    `json:"healthMonitorDelay"`
+	HealthMonitorTimeout       int    `json:"healthMonitorTimeout"`
+	HealthMonitorMaxRetries    int    `json:"healthMonitorMaxRetries"`
+	HealthMonitorHTTPPath      string `json:"healthMonitorHttpPath"`
+	HealthMonitorExpectedCodes string `json:"healthMonitorExpectedCodes"`
+	LBAlgorithm                string `json:"lbAlgorithm"`
+	SessionPersistence         string `json:"sessionPersistence"`
+}
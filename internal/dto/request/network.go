@@ -60,3 +60,43 @@ type FloatingIP struct {
 	FloatingNetworkID string `json:"floating_network_id"`
 	PortID            string `json:"port_id"`
 }
+
+type CreateNetworkRequest struct {
+	Network Network `json:"network"`
+}
+
+type Network struct {
+	Name         string `json:"name"`
+	AdminStateUp bool   `json:"admin_state_up"`
+}
+
+type CreateSubnetRequest struct {
+	Subnet Subnet `json:"subnet"`
+}
+
+type Subnet struct {
+	Name           string   `json:"name"`
+	NetworkID      string   `json:"network_id"`
+	IPVersion      int      `json:"ip_version"`
+	CIDR           string   `json:"cidr"`
+	EnableDHCP     bool     `json:"enable_dhcp"`
+	DNSNameservers []string `json:"dns_nameservers,omitempty"`
+}
+
+type CreateRouterRequest struct {
+	Router Router `json:"router"`
+}
+
+type Router struct {
+	Name                string              `json:"name"`
+	AdminStateUp        bool                `json:"admin_state_up"`
+	ExternalGatewayInfo ExternalGatewayInfo `json:"external_gateway_info"`
+}
+
+type ExternalGatewayInfo struct {
+	NetworkID string `json:"network_id"`
+}
+
+type RouterInterfaceRequest struct {
+	SubnetID string `json:"subnet_id"`
+}
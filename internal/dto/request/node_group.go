@@ -3,16 +3,75 @@ package request
 type UpdateNodeGroupRequest struct {
 	MinNodes *uint32 `json:"minNodes,omitempty"`
 	MaxNodes *uint32 `json:"maxNodes,omitempty"`
+	// DesiredNodes adds or removes nodes until the node group has this size.
+	DesiredNodes *uint32 `json:"desiredNodes,omitempty"`
+	// ScaleInPolicy decides what happens when the update would remove nodes,
+	// "reject" (default) fails the update, "drain" drains and removes the
+	// extra nodes.
+	ScaleInPolicy string `json:"scaleInPolicy,omitempty"`
 
-	Autoscale *bool `json:"autoscale,omitempty"`
+	Autoscale  *bool `json:"autoscale,omitempty"`
+	AutoRepair *bool `json:"autoRepair,omitempty"`
 }
 
 type CreateNodeGroupRequest struct {
-	NodeGroupName    string   `json:"nodeGroupName"`
-	NodeFlavorUUID   string   `json:"nodeFlavorUUID"`
-	NodeDiskSize     int      `json:"nodeDiskSize"`
-	NodeGroupLabels  []string `json:"nodeGroupLabels"`
-	NodeGroupTaints  []string `json:"nodeGroupTaints"`
-	NodeGroupMinSize int      `json:"nodeGroupMinSize"`
-	NodeGroupMaxSize int      `json:"nodeGroupMaxSize"`
+	NodeGroupName      string              `json:"nodeGroupName"`
+	NodeFlavorUUID     string              `json:"nodeFlavorUUID"`
+	NodeDiskSize       int                 `json:"nodeDiskSize"`
+	NodeGroupLabels    []string            `json:"nodeGroupLabels"`
+	NodeGroupTaints    []string            `json:"nodeGroupTaints"`
+	NodeGroupMinSize   int                 `json:"nodeGroupMinSize"`
+	NodeGroupMaxSize   int                 `json:"nodeGroupMaxSize"`
+	AutoRepair         bool                `json:"autoRepair"`
+	ServerGroupPolicy  string              `json:"serverGroupPolicy"`
+	ImageRef           string              `json:"imageRef"`
+	DataVolumes        []DataVolume        `json:"dataVolumes"`
+	UserData           UserDataExtensions  `json:"userData"`
+	AdditionalNetworks []AdditionalNetwork `json:"additionalNetworks"`
+	AvailabilityZones  []string            `json:"availabilityZones"`
+}
+
+// CloneNodeGroupRequest creates a node group from the stored settings of an
+// existing one, every field except the name is an optional override.
+type CloneNodeGroupRequest struct {
+	NodeGroupName      string              `json:"nodeGroupName"`
+	NodeFlavorUUID     *string             `json:"nodeFlavorUUID,omitempty"`
+	NodeDiskSize       *int                `json:"nodeDiskSize,omitempty"`
+	NodeGroupLabels    []string            `json:"nodeGroupLabels,omitempty"`
+	NodeGroupTaints    []string            `json:"nodeGroupTaints,omitempty"`
+	NodeGroupMinSize   *int                `json:"nodeGroupMinSize,omitempty"`
+	NodeGroupMaxSize   *int                `json:"nodeGroupMaxSize,omitempty"`
+	AutoRepair         *bool               `json:"autoRepair,omitempty"`
+	ServerGroupPolicy  *string             `json:"serverGroupPolicy,omitempty"`
+	ImageRef           *string             `json:"imageRef,omitempty"`
+	DataVolumes        []DataVolume        `json:"dataVolumes,omitempty"`
+	UserData           *UserDataExtensions `json:"userData,omitempty"`
+	AdditionalNetworks []AdditionalNetwork `json:"additionalNetworks,omitempty"`
+	AvailabilityZones  []string            `json:"availabilityZones,omitempty"`
+}
+
+type AdditionalNetwork struct {
+	SubnetID         string   `json:"subnetId"`
+	SecurityGroupIDs []string `json:"securityGroupIds"`
+}
+
+type UserDataExtensions struct {
+	PreInstallScript  string `json:"preInstallScript"`
+	PostInstallScript string `json:"postInstallScript"`
+	CloudConfig       string `json:"cloudConfig"`
+}
+
+type DataVolume struct {
+	SizeGB              int    `json:"sizeGB"`
+	VolumeType          string `json:"volumeType"`
+	DeleteOnTermination bool   `json:"deleteOnTermination"`
+}
+
+type CreateNodeGroupScheduleRequest struct {
+	CronExpression string  `json:"cronExpression"`
+	Timezone       string  `json:"timezone"`
+	MinNodes       *uint32 `json:"minNodes,omitempty"`
+	MaxNodes       *uint32 `json:"maxNodes,omitempty"`
+	DesiredNodes   *uint32 `json:"desiredNodes,omitempty"`
+	Enabled        *bool   `json:"enabled,omitempty"`
 }
@@ -6,9 +6,25 @@ type AddDNSRecordResponse struct {
 	Result  Result    `json:"result"`
 }
 
+type ListDNSRecordsResponse struct {
+	Errors     []CFError  `json:"errors"`
+	Success    bool       `json:"success"`
+	Result     []Result   `json:"result"`
+	ResultInfo ResultInfo `json:"result_info"`
+}
+
+type ResultInfo struct {
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
+}
+
 type Result struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	Comment string `json:"comment"`
+	TTL     int    `json:"ttl"`
 }
 
 type CFError struct {
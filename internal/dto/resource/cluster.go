@@ -26,6 +26,23 @@ type GetClusterDetailsResponse struct {
 	ClusterEndpoint              string      `json:"cluster_endpoint"`
 	ClusterAPIAccess             string      `json:"cluster_api_access"`
 	ClusterCertificateExpireDate time.Time   `json:"cluster_certificate_expire_date"`
+
+	ClusterLoadBalancerSettings LoadBalancerSettings `json:"cluster_load_balancer_settings"`
+	ClusterLoadBalancerProvider string               `json:"cluster_load_balancer_provider"`
+	ClusterLoadBalancerFlavorID string               `json:"cluster_load_balancer_flavor_id"`
+	ClusterDNSNames             []string             `json:"cluster_dns_names"`
+	ClusterTLSSANs              []string             `json:"cluster_tls_sans"`
+	ClusterAppsDNSTarget        string               `json:"cluster_apps_dns_target"`
+	ClusterPodCIDR              string               `json:"cluster_pod_cidr"`
+	ClusterServiceCIDR          string               `json:"cluster_service_cidr"`
+	ClusterDomain               string               `json:"cluster_domain"`
+	ClusterCNI                  string               `json:"cluster_cni"`
+	ClusterCNIOptions           CNIOptions           `json:"cluster_cni_options"`
+}
+
+type CNIOptions struct {
+	KubeProxyReplacement bool `json:"kube_proxy_replacement"`
+	MTU                  int  `json:"mtu,omitempty"`
 }
 
 type GetClusterResponse struct {
@@ -67,3 +84,33 @@ type UpdateKubeconfigResponse struct {
 type UpdateClusterResponse struct {
 	ClusterUUID string `json:"cluster_uuid"`
 }
+
+type ReplaceMasterResponse struct {
+	ClusterUUID      string `json:"cluster_uuid"`
+	ReplacedServerID string `json:"replaced_server_id"`
+	NewServerName    string `json:"new_server_name"`
+	ClusterStatus    string `json:"cluster_status"`
+}
+
+type ControlPlaneDriftReport struct {
+	ClusterUUID      string                  `json:"cluster_uuid"`
+	LoadBalancerUUID string                  `json:"load_balancer_uuid"`
+	InSync           bool                    `json:"in_sync"`
+	Reconciled       bool                    `json:"reconciled"`
+	Pools            []ControlPlanePoolDrift `json:"pools"`
+	CheckDate        time.Time               `json:"check_date"`
+}
+
+type ControlPlanePoolDrift struct {
+	PoolID         string               `json:"pool_id"`
+	ProtocolPort   int                  `json:"protocol_port"`
+	MissingMembers []ControlPlaneMember `json:"missing_members"`
+	StaleMembers   []ControlPlaneMember `json:"stale_members"`
+}
+
+type ControlPlaneMember struct {
+	MemberID string `json:"member_id,omitempty"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	SubnetID string `json:"subnet_id,omitempty"`
+}
@@ -1,5 +1,7 @@
 package resource
 
+import "time"
+
 type CreateComputeResponse struct {
 	Server Server `json:"server"`
 }
@@ -23,19 +25,62 @@ type OpenstacServersResponse struct {
 	OpenstackServers OpenstackServer `json:"server"`
 }
 type OpenstackServer struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	ID               string                     `json:"id"`
+	Name             string                     `json:"name"`
+	Status           string                     `json:"status"`
+	Created          time.Time                  `json:"created"`
+	Fault            Fault                      `json:"fault"`
+	Addresses        map[string][]ServerAddress `json:"addresses"`
+	AvailabilityZone string                     `json:"OS-EXT-AZ:availability_zone"`
+}
+
+type ServerAddress struct {
+	Addr    string `json:"addr"`
+	Version int    `json:"version"`
+	Type    string `json:"OS-EXT-IPS:type"`
+}
+
+type Fault struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
 }
 
 type Servers struct {
-	ClusterUUID   string `json:"cluster_uuid"`
-	Id            string `json:"id"`
-	NodeGroupUUID string `json:"node_group_uuid"`
-	MinSize       int    `json:"node_group_min_size"`
-	MaxSize       int    `json:"node_group_max_size"`
-	Flavor        string `json:"node_flavor_uuid"`
-	Status        string `json:"node_groups_status"`
+	ClusterUUID      string                `json:"cluster_uuid"`
+	Id               string                `json:"id"`
+	NodeGroupUUID    string                `json:"node_group_uuid"`
+	MinSize          int                   `json:"node_group_min_size"`
+	MaxSize          int                   `json:"node_group_max_size"`
+	Flavor           string                `json:"node_flavor_uuid"`
+	Status           string                `json:"node_groups_status"`
+	Volumes          []VolumeAttachment    `json:"volumes"`
+	Name             string                `json:"name"`
+	FixedIPs         []string              `json:"fixed_ips"`
+	AvailabilityZone string                `json:"availability_zone"`
+	CreatedAt        time.Time             `json:"created_at"`
+	KubernetesNode   *ServerKubernetesNode `json:"kubernetes_node"`
+}
+
+// ServerKubernetesNode is the kubernetes view of a server, it is nil when the
+// kubernetes API of the cluster can not be reached or the node is not registered.
+type ServerKubernetesNode struct {
+	Name           string            `json:"name"`
+	Ready          string            `json:"ready"`
+	ReadyReason    string            `json:"ready_reason,omitempty"`
+	KubeletVersion string            `json:"kubelet_version"`
+	Allocatable    map[string]string `json:"allocatable,omitempty"`
+	Taints         []KubernetesTaint `json:"taints,omitempty"`
+	Cordoned       bool              `json:"cordoned"`
+}
+
+type VolumeAttachmentsResponse struct {
+	VolumeAttachments []VolumeAttachment `json:"volumeAttachments"`
+}
+
+type VolumeAttachment struct {
+	VolumeID            string `json:"volumeId"`
+	Device              string `json:"device"`
+	DeleteOnTermination bool   `json:"delete_on_termination"`
 }
 
 type Flavor struct {
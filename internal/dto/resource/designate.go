@@ -0,0 +1,19 @@
+package resource
+
+type ListRecordSetsResponse struct {
+	RecordSets []RecordSetResponse `json:"recordsets"`
+	Links      RecordSetLinks      `json:"links"`
+}
+
+type RecordSetLinks struct {
+	Next string `json:"next"`
+}
+
+type RecordSetResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Records     []string `json:"records"`
+	TTL         int      `json:"ttl"`
+	Description string   `json:"description"`
+}
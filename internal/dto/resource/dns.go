@@ -0,0 +1,26 @@
+package resource
+
+import "time"
+
+type DNSRecord struct {
+	Provider string `json:"provider"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+	Comment  string `json:"comment"`
+	TTL      int    `json:"ttl"`
+}
+
+type DNSGarbageCollectionReport struct {
+	Provider       string            `json:"provider"`
+	ScannedRecords int               `json:"scanned_records"`
+	Orphans        []DNSOrphanRecord `json:"orphans"`
+	CheckDate      time.Time         `json:"check_date"`
+}
+
+type DNSOrphanRecord struct {
+	DNSRecord
+	Deleted bool   `json:"deleted"`
+	Error   string `json:"error,omitempty"`
+}
@@ -23,7 +23,13 @@ type GetTokenDetailsResponse struct {
 }
 
 type Token struct {
-	User User `json:"user"`
+	User  User   `json:"user"`
+	Roles []Role `json:"roles"`
+}
+
+type Role struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 type User struct {
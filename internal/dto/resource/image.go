@@ -0,0 +1,20 @@
+package resource
+
+type GlanceImage struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Visibility string `json:"visibility"`
+	Owner      string `json:"owner"`
+	OSDistro   string `json:"os_distro"`
+	OSVersion  string `json:"os_version"`
+	MinDisk    int    `json:"min_disk"`
+}
+
+type Image struct {
+	ImageRef  string `json:"image_ref"`
+	Name      string `json:"name"`
+	OSName    string `json:"os_name"`
+	OSVersion string `json:"os_version"`
+	Default   bool   `json:"default"`
+}
@@ -0,0 +1,74 @@
+package resource
+
+import "time"
+
+type KubernetesNodeListResponse struct {
+	Items []KubernetesNode `json:"items"`
+}
+
+type KubernetesNode struct {
+	Metadata KubernetesObjectMeta `json:"metadata"`
+	Spec     KubernetesNodeSpec   `json:"spec"`
+	Status   KubernetesNodeStatus `json:"status"`
+}
+
+type KubernetesObjectMeta struct {
+	Name              string                     `json:"name"`
+	Namespace         string                     `json:"namespace,omitempty"`
+	Labels            map[string]string          `json:"labels,omitempty"`
+	Annotations       map[string]string          `json:"annotations,omitempty"`
+	OwnerReferences   []KubernetesOwnerReference `json:"ownerReferences,omitempty"`
+	CreationTimestamp time.Time                  `json:"creationTimestamp"`
+}
+
+type KubernetesOwnerReference struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+type KubernetesNodeSpec struct {
+	ProviderID    string            `json:"providerID,omitempty"`
+	Unschedulable bool              `json:"unschedulable,omitempty"`
+	Taints        []KubernetesTaint `json:"taints,omitempty"`
+}
+
+type KubernetesTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect"`
+}
+
+type KubernetesNodeStatus struct {
+	Conditions  []KubernetesNodeCondition `json:"conditions"`
+	Allocatable map[string]string         `json:"allocatable,omitempty"`
+	NodeInfo    KubernetesNodeInfo        `json:"nodeInfo"`
+}
+
+type KubernetesNodeCondition struct {
+	Type               string    `json:"type"`
+	Status             string    `json:"status"`
+	Reason             string    `json:"reason,omitempty"`
+	LastTransitionTime time.Time `json:"lastTransitionTime"`
+}
+
+type KubernetesNodeInfo struct {
+	KubeletVersion string `json:"kubeletVersion"`
+}
+
+type KubernetesPodListResponse struct {
+	Items []KubernetesPod `json:"items"`
+}
+
+type KubernetesPod struct {
+	Metadata KubernetesObjectMeta `json:"metadata"`
+	Spec     KubernetesPodSpec    `json:"spec"`
+	Status   KubernetesPodStatus  `json:"status"`
+}
+
+type KubernetesPodSpec struct {
+	NodeName string `json:"nodeName,omitempty"`
+}
+
+type KubernetesPodStatus struct {
+	Phase string `json:"phase"`
+}
@@ -1,16 +1,24 @@
 package resource
 
+import "time"
+
 type ListLoadBalancerResponse struct {
 	LoadBalancer ListLoadBalancer `json:"loadbalancer"`
 }
 
 type ListLoadBalancer struct {
-	ID                 string `json:"id"`
-	Name               string `json:"name"`
-	ProvisioningStatus string `json:"provisioning_status"`
-	OperatingStatus    string `json:"operating_status"`
-	VIPAddress         string `json:"vip_address"`
-	VipPortID          string `json:"vip_port_id"`
+	ID                 string          `json:"id"`
+	Name               string          `json:"name"`
+	ProvisioningStatus string          `json:"provisioning_status"`
+	OperatingStatus    string          `json:"operating_status"`
+	VIPAddress         string          `json:"vip_address"`
+	VipPortID          string          `json:"vip_port_id"`
+	AdditionalVIPs     []AdditionalVIP `json:"additional_vips"`
+}
+
+type AdditionalVIP struct {
+	SubnetID  string `json:"subnet_id"`
+	IPAddress string `json:"ip_address"`
 }
 
 type CreateListenerResponse struct {
@@ -81,3 +89,90 @@ type GetLoadBalancerPoolsResponse struct {
 type GetLoadBalancerListenersResponse struct {
 	Listeners []string `json:"listeners"`
 }
+
+type GetPoolResponse struct {
+	Pool PoolDetail `json:"pool"`
+}
+
+type PoolDetail struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Protocol           string `json:"protocol"`
+	ProvisioningStatus string `json:"provisioning_status"`
+}
+
+type ListMembersResponse struct {
+	Members []PoolMember `json:"members"`
+}
+
+type PoolMember struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Address            string `json:"address"`
+	ProtocolPort       int    `json:"protocol_port"`
+	SubnetID           string `json:"subnet_id"`
+	OperatingStatus    string `json:"operating_status"`
+	ProvisioningStatus string `json:"provisioning_status"`
+}
+
+type IngressLoadBalancer struct {
+	ClusterUUID        string            `json:"cluster_uuid"`
+	NodeGroupUUID      string            `json:"node_group_uuid"`
+	LoadBalancerUUID   string            `json:"load_balancer_uuid"`
+	VIPAddress         string            `json:"vip_address"`
+	FloatingIP         string            `json:"floating_ip,omitempty"`
+	ProvisioningStatus string            `json:"provisioning_status"`
+	OperatingStatus    string            `json:"operating_status"`
+	Listeners          []IngressListener `json:"listeners"`
+	CreateDate         time.Time         `json:"create_date"`
+}
+
+type IngressListener struct {
+	Protocol   string `json:"protocol"`
+	Port       int    `json:"port"`
+	NodePort   int    `json:"node_port"`
+	ListenerID string `json:"listener_id,omitempty"`
+	PoolID     string `json:"pool_id,omitempty"`
+}
+
+type LoadBalancerSettings struct {
+	HealthMonitorType          string `json:"health_monitor_type"`
+	HealthMonitorDelay         int    `json:"health_monitor_delay"`
+	HealthMonitorTimeout       int    `json:"health_monitor_timeout"`
+	HealthMonitorMaxRetries    int    `json:"health_monitor_max_retries"`
+	HealthMonitorHTTPPath      string `json:"health_monitor_http_path"`
+	HealthMonitorExpectedCodes string `json:"health_monitor_expected_codes"`
+	LBAlgorithm                string `json:"lb_algorithm"`
+	SessionPersistence         string `json:"session_persistence"`
+}
+
+type ListLoadBalancerProvidersResponse struct {
+	Providers []LoadBalancerProvider `json:"providers"`
+}
+
+type LoadBalancerProvider struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ListLoadBalancerFlavorsResponse struct {
+	Flavors []LoadBalancerFlavor `json:"flavors"`
+}
+
+type LoadBalancerFlavor struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	Enabled         bool   `json:"enabled"`
+	FlavorProfileID string `json:"flavor_profile_id"`
+}
+
+type GetLoadBalancerFlavorProfileResponse struct {
+	FlavorProfile LoadBalancerFlavorProfile `json:"flavorprofile"`
+}
+
+type LoadBalancerFlavorProfile struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	ProviderName string `json:"provider_name"`
+}
@@ -22,6 +22,7 @@ type Port struct {
 }
 
 type FixedIp struct {
+	SubnetID  string `json:"subnet_id"`
 	IpAddress string `json:"ip_address"`
 }
 
@@ -68,3 +69,21 @@ type SecurityGroupRulesResponse struct {
 		ID string `json:"id"`
 	} `json:"security_group_rules"`
 }
+
+type CreateNetworkResponse struct {
+	Network Network `json:"network"`
+}
+
+type Network struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateRouterResponse struct {
+	Router Router `json:"router"`
+}
+
+type Router struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
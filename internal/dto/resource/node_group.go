@@ -1,5 +1,7 @@
 package resource
 
+import "time"
+
 type AddNodeResponse struct {
 	ClusterID   string `json:"clusterId"`
 	NodeGroupID string `json:"nodeGroupId"`
@@ -13,16 +15,33 @@ type GetNodeGroupsResponse struct {
 }
 
 type NodeGroup struct {
-	ClusterUUID      string `json:"cluster_uuid"`
-	NodeGroupUUID    string `json:"node_group_uuid"`
-	NodeGroupName    string `json:"node_group_name"`
-	NodeGroupMinSize int    `json:"node_group_min_size"`
-	NodeGroupMaxSize int    `json:"node_group_max_size"`
-	NodeDiskSize     int    `json:"node_disk_size"`
-	NodeFlavorUUID   string `json:"node_flavor_uuid"`
-	NodeGroupsType   string `json:"node_groups_type"`
-	CurrentNodes     int    `json:"current_nodes"`
-	NodeGroupsStatus string `json:"node_groups_status"`
+	ClusterUUID        string              `json:"cluster_uuid"`
+	NodeGroupUUID      string              `json:"node_group_uuid"`
+	NodeGroupName      string              `json:"node_group_name"`
+	NodeGroupMinSize   int                 `json:"node_group_min_size"`
+	NodeGroupMaxSize   int                 `json:"node_group_max_size"`
+	NodeDiskSize       int                 `json:"node_disk_size"`
+	NodeFlavorUUID     string              `json:"node_flavor_uuid"`
+	NodeGroupsType     string              `json:"node_groups_type"`
+	CurrentNodes       int                 `json:"current_nodes"`
+	NodeGroupsStatus   string              `json:"node_groups_status"`
+	AutoRepair         bool                `json:"auto_repair"`
+	ServerGroupPolicy  string              `json:"server_group_policy"`
+	ImageRef           string              `json:"image_ref"`
+	DataVolumes        []DataVolume        `json:"data_volumes"`
+	AdditionalNetworks []AdditionalNetwork `json:"additional_networks"`
+	AvailabilityZones  []string            `json:"availability_zones"`
+}
+
+type AdditionalNetwork struct {
+	SubnetID         string   `json:"subnet_id"`
+	SecurityGroupIDs []string `json:"security_group_ids"`
+}
+
+type DataVolume struct {
+	SizeGB              int    `json:"size_gb"`
+	VolumeType          string `json:"volume_type"`
+	DeleteOnTermination bool   `json:"delete_on_termination"`
 }
 
 type DeleteNodeResponse struct {
@@ -34,10 +53,51 @@ type UpdateNodeGroupResponse struct {
 	NodeGroupID string `json:"node_group_id"`
 	MinSize     int    `json:"min_size"`
 	MaxSize     int    `json:"max_size"`
+	DesiredSize int    `json:"desired_size"`
+	CurrentSize int    `json:"current_size"`
 	Status      string `json:"status"`
+	AutoRepair  bool   `json:"auto_repair"`
 }
 
 type CreateNodeGroupResponse struct {
 	ClusterID   string `json:"cluster_id"`
 	NodeGroupID string `json:"node_group_id"`
 }
+
+type NodeGroupSchedule struct {
+	ScheduleUUID   string     `json:"schedule_uuid"`
+	ClusterUUID    string     `json:"cluster_uuid"`
+	NodeGroupUUID  string     `json:"node_group_uuid"`
+	CronExpression string     `json:"cron_expression"`
+	Timezone       string     `json:"timezone"`
+	MinSize        *int       `json:"min_size,omitempty"`
+	MaxSize        *int       `json:"max_size,omitempty"`
+	DesiredSize    *int       `json:"desired_size,omitempty"`
+	Enabled        bool       `json:"enabled"`
+	LastRunDate    *time.Time `json:"last_run_date,omitempty"`
+	NextRunDate    *time.Time `json:"next_run_date,omitempty"`
+	CreateDate     time.Time  `json:"create_date"`
+}
+
+// NodeGroupDeleteImpact describes what would be lost by deleting a node group.
+// Deletion is refused while Blockers is not empty, and requires force when
+// RequiresForce is set.
+type NodeGroupDeleteImpact struct {
+	ClusterID     string         `json:"cluster_id"`
+	NodeGroupID   string         `json:"node_group_id"`
+	NodeGroupName string         `json:"node_group_name"`
+	NodeGroupType string         `json:"node_group_type"`
+	Deletable     bool           `json:"deletable"`
+	RequiresForce bool           `json:"requires_force"`
+	Blockers      []string       `json:"blockers"`
+	Warnings      []string       `json:"warnings"`
+	Nodes         []string       `json:"nodes"`
+	Pods          []NodeGroupPod `json:"pods"`
+}
+
+type NodeGroupPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	NodeName  string `json:"node_name"`
+	OwnerKind string `json:"owner_kind,omitempty"`
+}
@@ -35,10 +35,24 @@ type IAppHandler interface {
 	GetNodeGroups(c *fiber.Ctx) error
 	CreateNodeGroup(c *fiber.Ctx) error
 	GetClusterFlavor(c *fiber.Ctx) error
+	GetImageCatalog(c *fiber.Ctx) error
 	GetClusterErrors(c *fiber.Ctx) error
 	UpdateNodeGroups(c *fiber.Ctx) error
 	DeleteNode(c *fiber.Ctx) error
 	DeleteNodeGroup(c *fiber.Ctx) error
+	GetNodeGroupDeleteImpact(c *fiber.Ctx) error
+	ReplaceMaster(c *fiber.Ctx) error
+	CreateNodeGroupSchedule(c *fiber.Ctx) error
+	GetNodeGroupSchedules(c *fiber.Ctx) error
+	DeleteNodeGroupSchedule(c *fiber.Ctx) error
+	CloneNodeGroup(c *fiber.Ctx) error
+	CreateIngressLoadBalancer(c *fiber.Ctx) error
+	GetIngressLoadBalancer(c *fiber.Ctx) error
+	DeleteIngressLoadBalancer(c *fiber.Ctx) error
+	GetControlPlaneDrift(c *fiber.Ctx) error
+	ReconcileControlPlane(c *fiber.Ctx) error
+	GetDNSOrphans(c *fiber.Ctx) error
+	DeleteDNSOrphans(c *fiber.Ctx) error
 }
 
 type appHandler struct {
@@ -313,6 +327,21 @@ func (a *appHandler) GetClusterFlavor(c *fiber.Ctx) error {
 	}
 	return c.JSON(resp)
 }
+func (a *appHandler) GetImageCatalog(c *fiber.Ctx) error {
+	projectID := c.Params("project_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, "", "", ""))
+	}
+	resp, err := a.appService.Image().GetImageCatalog(ctx, authToken, projectID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToGetImageCatalogMsg, "", "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
 func (a *appHandler) UpdateNodeGroups(c *fiber.Ctx) error {
 	nodeGroupID := c.Params("nodegroup_id")
 	clusterID := c.Params("cluster_id")
@@ -326,7 +355,11 @@ func (a *appHandler) UpdateNodeGroups(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(
 			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, nodeGroupID, ""))
 	}
-	resp, _ := a.appService.NodeGroups().UpdateNodeGroups(ctx, authToken, clusterID, nodeGroupID, req)
+	resp, err := a.appService.NodeGroups().UpdateNodeGroups(ctx, authToken, clusterID, nodeGroupID, req)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToUpdateNodeGroupMsg, clusterID, nodeGroupID, ""))
+	}
 	return c.JSON(resp)
 }
 func (a *appHandler) DeleteNode(c *fiber.Ctx) error {
@@ -368,7 +401,8 @@ func (a *appHandler) DeleteNodeGroup(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(
 			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, nodeGroupID, ""))
 	}
-	err := a.appService.NodeGroups().DeleteNodeGroup(ctx, authToken, clusterID, nodeGroupID)
+	force := c.Query("force") == "true"
+	err := a.appService.NodeGroups().DeleteNodeGroup(ctx, authToken, clusterID, nodeGroupID, force)
 	if err != nil {
 		return c.Status(fiber.StatusUnprocessableEntity).JSON(
 			response.NewErrorResponseWithDetails(err, utils.FailedToDeleteNodeGroupMsg, clusterID, nodeGroupID, ""))
@@ -376,6 +410,23 @@ func (a *appHandler) DeleteNodeGroup(c *fiber.Ctx) error {
 	return c.JSON(err)
 }
 
+func (a *appHandler) GetNodeGroupDeleteImpact(c *fiber.Ctx) error {
+	nodeGroupID := c.Params("nodegroup_id")
+	clusterID := c.Params("cluster_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, nodeGroupID, ""))
+	}
+	resp, err := a.appService.NodeGroups().GetNodeGroupDeleteImpact(ctx, authToken, clusterID, nodeGroupID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToGetDeleteImpactMsg, clusterID, nodeGroupID, ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
 func (a *appHandler) UpdateKubeconfig(c *fiber.Ctx) error {
 	clusterID := c.Params("cluster_id")
 	var req request.UpdateKubeconfigRequest
@@ -421,3 +472,214 @@ func (a *appHandler) GetClusterErrors(c *fiber.Ctx) error {
 	resp, _ := a.appService.Cluster().GetClusterErrors(ctx, authToken, clusterID)
 	return c.JSON(response.NewSuccessResponse(resp))
 }
+
+func (a *appHandler) ReplaceMaster(c *fiber.Ctx) error {
+	clusterID := c.Params("cluster_id")
+	serverID := c.Params("server_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, "", ""))
+	}
+	resp, err := a.appService.Cluster().ReplaceMaster(ctx, authToken, clusterID, serverID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToReplaceMasterMsg, clusterID, "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) CreateNodeGroupSchedule(c *fiber.Ctx) error {
+	nodeGroupID := c.Params("nodegroup_id")
+	clusterID := c.Params("cluster_id")
+	var req request.CreateNodeGroupScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.BodyParserMsg, clusterID, nodeGroupID, ""))
+	}
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, nodeGroupID, ""))
+	}
+	resp, err := a.appService.NodeGroups().CreateNodeGroupSchedule(ctx, authToken, clusterID, nodeGroupID, req)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToCreateNodeGroupScheduleMsg, clusterID, nodeGroupID, ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) GetNodeGroupSchedules(c *fiber.Ctx) error {
+	nodeGroupID := c.Params("nodegroup_id")
+	clusterID := c.Params("cluster_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, nodeGroupID, ""))
+	}
+	resp, err := a.appService.NodeGroups().GetNodeGroupSchedules(ctx, authToken, clusterID, nodeGroupID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToGetNodeGroupSchedulesMsg, clusterID, nodeGroupID, ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) DeleteNodeGroupSchedule(c *fiber.Ctx) error {
+	nodeGroupID := c.Params("nodegroup_id")
+	clusterID := c.Params("cluster_id")
+	scheduleID := c.Params("schedule_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, nodeGroupID, ""))
+	}
+	err := a.appService.NodeGroups().DeleteNodeGroupSchedule(ctx, authToken, clusterID, nodeGroupID, scheduleID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToDeleteNodeGroupScheduleMsg, clusterID, nodeGroupID, ""))
+	}
+	return c.JSON(response.NewSuccessResponse(nil))
+}
+
+func (a *appHandler) CloneNodeGroup(c *fiber.Ctx) error {
+	nodeGroupID := c.Params("nodegroup_id")
+	clusterID := c.Params("cluster_id")
+	var req request.CloneNodeGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.BodyParserMsg, clusterID, nodeGroupID, ""))
+	}
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, nodeGroupID, ""))
+	}
+	resp, err := a.appService.NodeGroups().CloneNodeGroup(ctx, authToken, clusterID, nodeGroupID, req)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToCloneNodeGroupMsg, clusterID, nodeGroupID, ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) CreateIngressLoadBalancer(c *fiber.Ctx) error {
+	clusterID := c.Params("cluster_id")
+	var req request.CreateIngressLoadBalancerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.BodyParserMsg, clusterID, "", ""))
+	}
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, "", ""))
+	}
+	resp, err := a.appService.Ingress().CreateIngressLoadBalancer(ctx, authToken, clusterID, req)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToCreateIngressLoadBalancerMsg, clusterID, req.NodeGroupID, ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) GetIngressLoadBalancer(c *fiber.Ctx) error {
+	clusterID := c.Params("cluster_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, "", ""))
+	}
+	resp, err := a.appService.Ingress().GetIngressLoadBalancer(ctx, authToken, clusterID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToGetIngressLoadBalancerMsg, clusterID, "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) DeleteIngressLoadBalancer(c *fiber.Ctx) error {
+	clusterID := c.Params("cluster_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, "", ""))
+	}
+	err := a.appService.Ingress().DeleteIngressLoadBalancer(ctx, authToken, clusterID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToDeleteIngressLoadBalancerMsg, clusterID, "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(nil))
+}
+
+func (a *appHandler) GetControlPlaneDrift(c *fiber.Ctx) error {
+	clusterID := c.Params("cluster_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, "", ""))
+	}
+	resp, err := a.appService.Cluster().GetControlPlaneDrift(ctx, authToken, clusterID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToGetDriftMsg, clusterID, "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) ReconcileControlPlane(c *fiber.Ctx) error {
+	clusterID := c.Params("cluster_id")
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, clusterID, "", ""))
+	}
+	resp, err := a.appService.Cluster().ReconcileControlPlane(ctx, authToken, clusterID)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToReconcileMsg, clusterID, "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) GetDNSOrphans(c *fiber.Ctx) error {
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, "", "", ""))
+	}
+	resp, err := a.appService.DNSGarbageCollector().GetOrphanRecords(ctx, authToken)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToGetDNSOrphansMsg, "", "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
+
+func (a *appHandler) DeleteDNSOrphans(c *fiber.Ctx) error {
+	ctx := context.Background()
+	authToken := c.Get("X-Auth-Token")
+	if authToken == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			response.NewErrorResponseWithDetails(fiber.ErrUnauthorized, utils.UnauthorizedMsg, "", "", ""))
+	}
+	resp, err := a.appService.DNSGarbageCollector().DeleteOrphanRecords(ctx, authToken)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(
+			response.NewErrorResponseWithDetails(err, utils.FailedToDeleteDNSOrphansMsg, "", "", ""))
+	}
+	return c.JSON(response.NewSuccessResponse(resp))
+}
@@ -25,10 +25,23 @@ type Cluster struct {
 	ClusterEndpoint              string         `json:"cluster_endpoint" gorm:"type:varchar(144)"`
 	ClusterAPIAccess             string         `json:"cluster_api_access" gorm:"type:varchar(255)"`
 	FloatingIPUUID               string         `json:"floating_ip_uuid" gorm:"type:varchar(36)"`
-	ClusterCloudflareRecordID    string         `json:"cluster_cloudflare_record_id" gorm:"type:varchar(36)"`
+	ClusterDNSProvider           string         `json:"cluster_dns_provider" gorm:"column:cluster_dns_provider;type:varchar(32)"`
+	ClusterDNSRecordID           string         `json:"cluster_dns_record_id" gorm:"column:cluster_dns_record_id;type:varchar(255)"`
+	ClusterDNSRecords            datatypes.JSON `json:"cluster_dns_records" gorm:"column:cluster_dns_records;type:json"`
+	ClusterTLSSANs               datatypes.JSON `json:"cluster_tls_sans" gorm:"column:cluster_tls_sans;type:json"`
+	ClusterAppsDNSTarget         string         `json:"cluster_apps_dns_target" gorm:"column:cluster_apps_dns_target;type:varchar(64)"`
+	ClusterPodCIDR               string         `json:"cluster_pod_cidr" gorm:"column:cluster_pod_cidr;type:varchar(128)"`
+	ClusterServiceCIDR           string         `json:"cluster_service_cidr" gorm:"column:cluster_service_cidr;type:varchar(128)"`
+	ClusterDomain                string         `json:"cluster_domain" gorm:"column:cluster_domain;type:varchar(253)"`
+	ClusterCNI                   string         `json:"cluster_cni" gorm:"column:cluster_cni;type:varchar(32)"`
+	ClusterCNIOptions            datatypes.JSON `json:"cluster_cni_options" gorm:"column:cluster_cni_options;type:json"`
 	ClusterSharedSecurityGroup   string         `json:"cluster_shared_security_group" gorm:"type:varchar(50)"`
 	ApplicationCredentialID      string         `json:"application_credential_id" gorm:"type:varchar(36)"`
-	DeleteState                  string         `json:"delete_state" gorm:"column:delete_state;type:enum('INITIAL','LOADBALANCER','DNS','FLOATING_IP','NODES','PORTS','SECURITY_GROUPS','CREDENTIALS','COMPLETED')"`
+	ClusterUserData              datatypes.JSON `json:"cluster_user_data" gorm:"type:json"`
+	ClusterLBSettings            datatypes.JSON `json:"cluster_load_balancer_settings" gorm:"column:cluster_load_balancer_settings;type:json"`
+	ClusterLBProvider            string         `json:"cluster_load_balancer_provider" gorm:"column:cluster_load_balancer_provider;type:varchar(64)"`
+	ClusterLBFlavorID            string         `json:"cluster_load_balancer_flavor_id" gorm:"column:cluster_load_balancer_flavor_id;type:varchar(36)"`
+	DeleteState                  string         `json:"delete_state" gorm:"column:delete_state;type:enum('INITIAL','LOADBALANCER','DNS','FLOATING_IP','NODES','PORTS','SECURITY_GROUPS','NETWORK','CREDENTIALS','COMPLETED')"`
 }
 
 func (Cluster) TableName() string {
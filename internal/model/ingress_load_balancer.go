@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type IngressLoadBalancer struct {
+	ID               int64          `json:"-" gorm:"primary_key;auto_increment"`
+	ClusterUUID      string         `json:"cluster_uuid" gorm:"type:varchar(36)"`
+	NodeGroupUUID    string         `json:"node_group_uuid" gorm:"type:varchar(36)"`
+	LoadBalancerUUID string         `json:"load_balancer_uuid" gorm:"type:varchar(36)"`
+	VIPAddress       string         `json:"vip_address" gorm:"type:varchar(64)"`
+	FloatingIPUUID   string         `json:"floating_ip_uuid" gorm:"type:varchar(36)"`
+	FloatingIP       string         `json:"floating_ip" gorm:"type:varchar(64)"`
+	Listeners        datatypes.JSON `json:"listeners" gorm:"type:json"`
+	CreateDate       time.Time      `json:"create_date" gorm:"type:datetime"`
+	UpdateDate       time.Time      `json:"update_date" gorm:"type:datetime;default:null"`
+}
+
+func (IngressLoadBalancer) TableName() string {
+	return "ingress_load_balancers"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type NodeGroupSchedule struct {
+	ID             int64      `json:"-" gorm:"primary_key;auto_increment"`
+	ScheduleUUID   string     `json:"schedule_uuid" gorm:"type:varchar(36)"`
+	ClusterUUID    string     `json:"cluster_uuid" gorm:"type:varchar(36)"`
+	NodeGroupUUID  string     `json:"node_group_uuid" gorm:"type:varchar(36)"`
+	CronExpression string     `json:"cron_expression" gorm:"type:varchar(100)"`
+	Timezone       string     `json:"timezone" gorm:"type:varchar(64)"`
+	MinSize        *int       `json:"min_size" gorm:"type:int(11)"`
+	MaxSize        *int       `json:"max_size" gorm:"type:int(11)"`
+	DesiredSize    *int       `json:"desired_size" gorm:"type:int(11)"`
+	Enabled        bool       `json:"enabled" gorm:"type:tinyint(1)"`
+	LastRunDate    *time.Time `json:"last_run_date" gorm:"type:datetime;default:null"`
+	CreateDate     time.Time  `json:"create_date" gorm:"type:datetime"`
+	UpdateDate     time.Time  `json:"update_date" gorm:"type:datetime;default:null"`
+}
+
+func (NodeGroupSchedule) TableName() string {
+	return "node_group_schedules"
+}
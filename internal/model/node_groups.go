@@ -24,6 +24,13 @@ type NodeGroups struct {
 	NodeGroupUpdateDate    time.Time      `json:"node_group_update_date" gorm:"type:datetime;default:null"`
 	NodeGroupDeleteDate    time.Time      `json:"node_group_delete_date" gorm:"type:datetime;default:null"`
 	NodeGroupSecurityGroup string         `json:"node_group_security_group" gorm:"type:varchar(50)"`
+	NodeGroupAutoRepair    bool           `json:"node_group_auto_repair" gorm:"type:tinyint(1)"`
+	ServerGroupPolicy      string         `json:"server_group_policy" gorm:"type:varchar(20)"`
+	NodeImageRef           string         `json:"node_image_ref" gorm:"type:varchar(36)"`
+	NodeGroupDataVolumes   datatypes.JSON `json:"node_group_data_volumes" gorm:"type:json"`
+	NodeGroupUserData      datatypes.JSON `json:"node_group_user_data" gorm:"type:json"`
+	NodeGroupNetworks      datatypes.JSON `json:"node_group_networks" gorm:"type:json"`
+	NodeGroupZones         datatypes.JSON `json:"node_group_availability_zones" gorm:"column:node_group_availability_zones;type:json"`
 }
 
 func (NodeGroups) TableName() string {
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/vmindtech/vke/internal/model"
 	"github.com/vmindtech/vke/pkg/mysqldb"
@@ -9,6 +11,7 @@ import (
 
 type IAuditLogRepository interface {
 	CreateAuditLog(ctx context.Context, auditLog *model.AuditLog) error
+	CountAuditLogs(ctx context.Context, clusterUUID, eventPattern string, since time.Time) (int64, error)
 }
 
 type AuditLogRepository struct {
@@ -28,3 +31,30 @@ func (a *AuditLogRepository) CreateAuditLog(ctx context.Context, auditLog *model
 		Create(auditLog).
 		Error
 }
+
+// likeEscapeChar escapes the LIKE wildcards, unlike a backslash it does not
+// depend on the sql mode.
+const likeEscapeChar = "!"
+
+var likePatternEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// EscapeLikePattern makes a value match itself in a CountAuditLogs pattern.
+func EscapeLikePattern(value string) string {
+	return likePatternEscaper.Replace(value)
+}
+
+// CountAuditLogs counts the events of a cluster matching the LIKE pattern
+// which were created after since. Literal parts of the pattern must be
+// escaped with EscapeLikePattern.
+func (a *AuditLogRepository) CountAuditLogs(ctx context.Context, clusterUUID, eventPattern string, since time.Time) (int64, error) {
+	var count int64
+	err := a.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Model(&model.AuditLog{}).
+		Where("cluster_uuid = ? AND event LIKE ? ESCAPE '"+likeEscapeChar+"' AND create_date > ?", clusterUUID, eventPattern, since).
+		Count(&count).
+		Error
+
+	return count, err
+}
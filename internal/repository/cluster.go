@@ -6,14 +6,18 @@ import (
 
 	"github.com/vmindtech/vke/internal/model"
 	"github.com/vmindtech/vke/pkg/mysqldb"
+	"gorm.io/datatypes"
 )
 
 type IClusterRepository interface {
 	GetClusterByUUID(ctx context.Context, uuid string) (*model.Cluster, error)
 	GetClustersByProjectId(ctx context.Context, projectId string) ([]model.Cluster, error)
+	GetClustersByStatus(ctx context.Context, status string) ([]model.Cluster, error)
+	GetClusters(ctx context.Context) ([]model.Cluster, error)
 	CreateCluster(ctx context.Context, cluster *model.Cluster) error
 	UpdateCluster(ctx context.Context, cluster *model.Cluster) error
 	DeleteUpdateCluster(ctx context.Context, cluster *model.Cluster, clusterUUID string) error
+	UpdateClusterDNSRecords(ctx context.Context, clusterUUID string, records datatypes.JSON) error
 }
 
 type ClusterRepository struct {
@@ -61,6 +65,38 @@ func (c *ClusterRepository) GetClustersByProjectId(ctx context.Context, projectI
 	return clusters, nil
 }
 
+func (c *ClusterRepository) GetClusters(ctx context.Context) ([]model.Cluster, error) {
+	var clusters []model.Cluster
+
+	err := c.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Find(&clusters).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return clusters, nil
+}
+
+func (c *ClusterRepository) GetClustersByStatus(ctx context.Context, status string) ([]model.Cluster, error) {
+	var clusters []model.Cluster
+
+	err := c.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.Cluster{ClusterStatus: status}).
+		Find(&clusters).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return clusters, nil
+}
+
 func (c *ClusterRepository) CreateCluster(ctx context.Context, cluster *model.Cluster) error {
 	return c.mysqlInstance.
 		Database().
@@ -92,3 +128,15 @@ func (c *ClusterRepository) DeleteUpdateCluster(ctx context.Context, cluster *mo
 		}).
 		Error
 }
+
+// UpdateClusterDNSRecords only writes the DNS records so a stale cluster
+// loaded by a background task does not overwrite other columns.
+func (c *ClusterRepository) UpdateClusterDNSRecords(ctx context.Context, clusterUUID string, records datatypes.JSON) error {
+	return c.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Model(&model.Cluster{}).
+		Where(&model.Cluster{ClusterUUID: clusterUUID}).
+		Update("cluster_dns_records", records).
+		Error
+}
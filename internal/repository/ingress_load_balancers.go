@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/pkg/mysqldb"
+)
+
+type IIngressLoadBalancersRepository interface {
+	CreateIngressLoadBalancer(ctx context.Context, ingressLoadBalancer *model.IngressLoadBalancer) error
+	GetIngressLoadBalancerByClusterUUID(ctx context.Context, clusterUUID string) (*model.IngressLoadBalancer, error)
+	UpdateIngressLoadBalancer(ctx context.Context, ingressLoadBalancer *model.IngressLoadBalancer) error
+	DeleteIngressLoadBalancer(ctx context.Context, clusterUUID string) error
+}
+
+type IngressLoadBalancersRepository struct {
+	mysqlInstance mysqldb.IMysqlInstance
+}
+
+func NewIngressLoadBalancersRepository(mysqlInstance mysqldb.IMysqlInstance) *IngressLoadBalancersRepository {
+	return &IngressLoadBalancersRepository{
+		mysqlInstance: mysqlInstance,
+	}
+}
+
+func (i *IngressLoadBalancersRepository) CreateIngressLoadBalancer(ctx context.Context, ingressLoadBalancer *model.IngressLoadBalancer) error {
+	return i.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Create(ingressLoadBalancer).
+		Error
+}
+
+// GetIngressLoadBalancerByClusterUUID returns nil when the cluster has no
+// ingress load balancer.
+func (i *IngressLoadBalancersRepository) GetIngressLoadBalancerByClusterUUID(ctx context.Context, clusterUUID string) (*model.IngressLoadBalancer, error) {
+	var ingressLoadBalancers []model.IngressLoadBalancer
+
+	err := i.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.IngressLoadBalancer{ClusterUUID: clusterUUID}).
+		Limit(1).
+		Find(&ingressLoadBalancers).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	if len(ingressLoadBalancers) == 0 {
+		return nil, nil
+	}
+
+	return &ingressLoadBalancers[0], nil
+}
+
+func (i *IngressLoadBalancersRepository) UpdateIngressLoadBalancer(ctx context.Context, ingressLoadBalancer *model.IngressLoadBalancer) error {
+	ingressLoadBalancer.UpdateDate = time.Now()
+	return i.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.IngressLoadBalancer{ClusterUUID: ingressLoadBalancer.ClusterUUID}).
+		Updates(ingressLoadBalancer).
+		Error
+}
+
+func (i *IngressLoadBalancersRepository) DeleteIngressLoadBalancer(ctx context.Context, clusterUUID string) error {
+	return i.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.IngressLoadBalancer{ClusterUUID: clusterUUID}).
+		Delete(&model.IngressLoadBalancer{}).
+		Error
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/pkg/mysqldb"
+)
+
+type INodeGroupSchedulesRepository interface {
+	CreateSchedule(ctx context.Context, schedule *model.NodeGroupSchedule) error
+	GetSchedulesByNodeGroupUUID(ctx context.Context, nodeGroupUUID string) ([]model.NodeGroupSchedule, error)
+	GetScheduleByUUID(ctx context.Context, scheduleUUID string) (*model.NodeGroupSchedule, error)
+	GetEnabledSchedules(ctx context.Context) ([]model.NodeGroupSchedule, error)
+	UpdateScheduleLastRun(ctx context.Context, scheduleUUID string, lastRunDate time.Time) error
+	DeleteSchedule(ctx context.Context, scheduleUUID string) error
+	DeleteSchedulesByNodeGroupUUID(ctx context.Context, nodeGroupUUID string) error
+}
+
+type NodeGroupSchedulesRepository struct {
+	mysqlInstance mysqldb.IMysqlInstance
+}
+
+func NewNodeGroupSchedulesRepository(mysqlInstance mysqldb.IMysqlInstance) *NodeGroupSchedulesRepository {
+	return &NodeGroupSchedulesRepository{
+		mysqlInstance: mysqlInstance,
+	}
+}
+
+func (n *NodeGroupSchedulesRepository) CreateSchedule(ctx context.Context, schedule *model.NodeGroupSchedule) error {
+	return n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Create(schedule).
+		Error
+}
+
+func (n *NodeGroupSchedulesRepository) GetSchedulesByNodeGroupUUID(ctx context.Context, nodeGroupUUID string) ([]model.NodeGroupSchedule, error) {
+	var schedules []model.NodeGroupSchedule
+
+	err := n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.NodeGroupSchedule{NodeGroupUUID: nodeGroupUUID}).
+		Order("create_date ASC").
+		Find(&schedules).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (n *NodeGroupSchedulesRepository) GetScheduleByUUID(ctx context.Context, scheduleUUID string) (*model.NodeGroupSchedule, error) {
+	var schedule model.NodeGroupSchedule
+
+	err := n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.NodeGroupSchedule{ScheduleUUID: scheduleUUID}).
+		First(&schedule).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (n *NodeGroupSchedulesRepository) GetEnabledSchedules(ctx context.Context) ([]model.NodeGroupSchedule, error) {
+	var schedules []model.NodeGroupSchedule
+
+	err := n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.NodeGroupSchedule{Enabled: true}).
+		Find(&schedules).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (n *NodeGroupSchedulesRepository) UpdateScheduleLastRun(ctx context.Context, scheduleUUID string, lastRunDate time.Time) error {
+	return n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Model(&model.NodeGroupSchedule{}).
+		Where(&model.NodeGroupSchedule{ScheduleUUID: scheduleUUID}).
+		Updates(map[string]interface{}{"last_run_date": lastRunDate, "update_date": time.Now()}).
+		Error
+}
+
+func (n *NodeGroupSchedulesRepository) DeleteSchedule(ctx context.Context, scheduleUUID string) error {
+	return n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.NodeGroupSchedule{ScheduleUUID: scheduleUUID}).
+		Delete(&model.NodeGroupSchedule{}).
+		Error
+}
+
+func (n *NodeGroupSchedulesRepository) DeleteSchedulesByNodeGroupUUID(ctx context.Context, nodeGroupUUID string) error {
+	return n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.NodeGroupSchedule{NodeGroupUUID: nodeGroupUUID}).
+		Delete(&model.NodeGroupSchedule{}).
+		Error
+}
@@ -15,6 +15,9 @@ type INodeGroupsRepository interface {
 	UpdateNodeGroups(ctx context.Context, nodeGroups *model.NodeGroups) error
 	GetNodeGroupByUUID(ctx context.Context, uuid string) (*model.NodeGroups, error)
 	GetClusterProjectUUIDByNodeGroupUUID(ctx context.Context, nodeGroupUUID string) (string, error)
+	GetAutoRepairNodeGroups(ctx context.Context) ([]model.NodeGroups, error)
+	UpdateNodeGroupAutoRepair(ctx context.Context, nodeGroupUUID string, autoRepair bool) error
+	UpdateNodeGroupSize(ctx context.Context, nodeGroupUUID string, minSize, maxSize int) error
 }
 
 type NodeGroupsRepository struct {
@@ -105,3 +108,47 @@ func (c *NodeGroupsRepository) GetClusterProjectUUIDByNodeGroupUUID(ctx context.
 	}
 	return nodeGroup.ClusterUUID, nil
 }
+
+func (n *NodeGroupsRepository) GetAutoRepairNodeGroups(ctx context.Context) ([]model.NodeGroups, error) {
+	var nodeGroups []model.NodeGroups
+
+	err := n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where(&model.NodeGroups{NodeGroupAutoRepair: true, NodeGroupsType: "worker", NodeGroupsStatus: "Active"}).
+		Find(&nodeGroups).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return nodeGroups, nil
+}
+
+// UpdateNodeGroupAutoRepair updates the column explicitly since Updates
+// ignores false values of a struct.
+func (n *NodeGroupsRepository) UpdateNodeGroupAutoRepair(ctx context.Context, nodeGroupUUID string, autoRepair bool) error {
+	return n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Model(&model.NodeGroups{}).
+		Where(&model.NodeGroups{NodeGroupUUID: nodeGroupUUID}).
+		Update("node_group_auto_repair", autoRepair).
+		Error
+}
+
+// UpdateNodeGroupSize updates the columns explicitly since Updates ignores a
+// min size of zero.
+func (n *NodeGroupsRepository) UpdateNodeGroupSize(ctx context.Context, nodeGroupUUID string, minSize, maxSize int) error {
+	return n.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Model(&model.NodeGroups{}).
+		Where(&model.NodeGroups{NodeGroupUUID: nodeGroupUUID}).
+		Updates(map[string]interface{}{
+			"node_group_min_size": minSize,
+			"node_group_max_size": maxSize,
+		}).
+		Error
+}
@@ -14,6 +14,8 @@ type IRepository interface {
 	NodeGroups() INodeGroupsRepository
 	Resources() IResourcesRepository
 	Error() IErrorRepository
+	NodeGroupSchedules() INodeGroupSchedulesRepository
+	IngressLoadBalancers() IIngressLoadBalancersRepository
 	StartDBTransaction(ctx context.Context) (*gorm.DB, error)
 	CommitDBTransaction(tx *gorm.DB) error
 }
@@ -26,9 +28,11 @@ type repository struct {
 	nodegroups    INodeGroupsRepository
 	resources     IResourcesRepository
 	err           IErrorRepository
+	schedules     INodeGroupSchedulesRepository
+	ingress       IIngressLoadBalancersRepository
 }
 
-func NewRepository(mi mysqldb.IMysqlInstance, cr IClusterRepository, ar IAuditLogRepository, kr IKubeconfigRepository, ng INodeGroupsRepository, rr IResourcesRepository, er IErrorRepository) IRepository {
+func NewRepository(mi mysqldb.IMysqlInstance, cr IClusterRepository, ar IAuditLogRepository, kr IKubeconfigRepository, ng INodeGroupsRepository, rr IResourcesRepository, er IErrorRepository, sr INodeGroupSchedulesRepository, ir IIngressLoadBalancersRepository) IRepository {
 	return &repository{
 		mysqlInstance: mi,
 		cluster:       cr,
@@ -37,6 +41,8 @@ func NewRepository(mi mysqldb.IMysqlInstance, cr IClusterRepository, ar IAuditLo
 		nodegroups:    ng,
 		resources:     rr,
 		err:           er,
+		schedules:     sr,
+		ingress:       ir,
 	}
 }
 
@@ -85,3 +91,11 @@ func (r *repository) Resources() IResourcesRepository {
 func (r *repository) Error() IErrorRepository {
 	return r.err
 }
+
+func (r *repository) NodeGroupSchedules() INodeGroupSchedulesRepository {
+	return r.schedules
+}
+
+func (r *repository) IngressLoadBalancers() IIngressLoadBalancersRepository {
+	return r.ingress
+}
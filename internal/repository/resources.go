@@ -10,6 +10,7 @@ import (
 type IResourcesRepository interface {
 	CreateResource(ctx context.Context, resource *model.Resource) error
 	GetResourceByClusterUUID(ctx context.Context, clusterUUID string, resourceType string) ([]model.Resource, error)
+	DeleteResource(ctx context.Context, clusterUUID, resourceType, resourceUUID string) error
 }
 
 type ResourcesRepository struct {
@@ -38,3 +39,11 @@ func (c *ResourcesRepository) GetResourceByClusterUUID(ctx context.Context, clus
 		Find(&resources).
 		Error
 }
+func (c *ResourcesRepository) DeleteResource(ctx context.Context, clusterUUID, resourceType, resourceUUID string) error {
+	return c.mysqlInstance.
+		Database().
+		WithContext(ctx).
+		Where("cluster_uuid = ? AND resource_type = ? AND resource_uuid = ?", clusterUUID, resourceType, resourceUUID).
+		Delete(&model.Resource{}).
+		Error
+}
@@ -59,6 +59,20 @@ func (r *route) appRoutes(fr fiber.Router) {
 	appGroup.Get("/cluster/:cluster_id/nodegroups/:nodegroup_id/nodes", r.appHandler.GetNodes)
 	appGroup.Delete("/cluster/:cluster_id/nodegroups/:nodegroup_id/nodes/:id", r.appHandler.DeleteNode)
 	appGroup.Delete("/cluster/:cluster_id/nodegroups/:nodegroup_id", r.appHandler.DeleteNodeGroup)
+	appGroup.Get("/cluster/:cluster_id/nodegroups/:nodegroup_id/delete-impact", r.appHandler.GetNodeGroupDeleteImpact)
+	appGroup.Post("/cluster/:cluster_id/nodegroups/:nodegroup_id/clone", r.appHandler.CloneNodeGroup)
+	appGroup.Get("/cluster/:cluster_id/nodegroups/:nodegroup_id/schedules", r.appHandler.GetNodeGroupSchedules)
+	appGroup.Post("/cluster/:cluster_id/nodegroups/:nodegroup_id/schedules", r.appHandler.CreateNodeGroupSchedule)
+	appGroup.Delete("/cluster/:cluster_id/nodegroups/:nodegroup_id/schedules/:schedule_id", r.appHandler.DeleteNodeGroupSchedule)
 	appGroup.Get("/cluster/:cluster_id/flavors", r.appHandler.GetClusterFlavor)
 	appGroup.Get("/cluster/:cluster_id/errors", r.appHandler.GetClusterErrors)
+	appGroup.Post("/cluster/:cluster_id/masters/:server_id/replace", r.appHandler.ReplaceMaster)
+	appGroup.Get("/cluster/:cluster_id/control-plane/drift", r.appHandler.GetControlPlaneDrift)
+	appGroup.Post("/cluster/:cluster_id/control-plane/reconcile", r.appHandler.ReconcileControlPlane)
+	appGroup.Get("/cluster/:cluster_id/ingress-loadbalancer", r.appHandler.GetIngressLoadBalancer)
+	appGroup.Post("/cluster/:cluster_id/ingress-loadbalancer", r.appHandler.CreateIngressLoadBalancer)
+	appGroup.Delete("/cluster/:cluster_id/ingress-loadbalancer", r.appHandler.DeleteIngressLoadBalancer)
+	appGroup.Get("/images/project/:project_id", r.appHandler.GetImageCatalog)
+	appGroup.Get("/admin/dns/orphans", r.appHandler.GetDNSOrphans)
+	appGroup.Delete("/admin/dns/orphans", r.appHandler.DeleteDNSOrphans)
 }
@@ -9,6 +9,9 @@ type IAppService interface {
 	Cluster() IClusterService
 	Compute() IComputeService
 	NodeGroups() INodeGroupsService
+	Image() IImageService
+	Ingress() IIngressService
+	DNSGarbageCollector() IDNSGarbageCollectorService
 }
 
 type appService struct {
@@ -17,15 +20,21 @@ type appService struct {
 	clusterService    IClusterService
 	computeService    IComputeService
 	nodeGroupsService INodeGroupsService
+	imageService      IImageService
+	ingressService    IIngressService
+	dnsGCService      IDNSGarbageCollectorService
 }
 
-func NewAppService(l *logrus.Logger, r repository.IRepository, cs IClusterService, coms IComputeService, nodg INodeGroupsService) IAppService {
+func NewAppService(l *logrus.Logger, r repository.IRepository, cs IClusterService, coms IComputeService, nodg INodeGroupsService, is IImageService, ig IIngressService, dgc IDNSGarbageCollectorService) IAppService {
 	return &appService{
 		logger:            l,
 		repository:        r,
 		clusterService:    cs,
 		computeService:    coms,
 		nodeGroupsService: nodg,
+		imageService:      is,
+		ingressService:    ig,
+		dnsGCService:      dgc,
 	}
 }
 
@@ -38,3 +47,12 @@ func (a *appService) Compute() IComputeService {
 func (a *appService) NodeGroups() INodeGroupsService {
 	return a.nodeGroupsService
 }
+func (a *appService) Image() IImageService {
+	return a.imageService
+}
+func (a *appService) Ingress() IIngressService {
+	return a.ingressService
+}
+func (a *appService) DNSGarbageCollector() IDNSGarbageCollectorService {
+	return a.dnsGCService
+}
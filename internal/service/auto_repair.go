@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/internal/repository"
+)
+
+const (
+	ServerStatusError   = "ERROR"
+	ServerStatusShutoff = "SHUTOFF"
+
+	// autoRepairStartedEvent is also matched to count the repairs of a node
+	// group for the rate limit.
+	autoRepairStartedEvent = "Auto repair started for node %s in node group %s, reason: %s"
+)
+
+type IAutoRepairService interface {
+	Start(ctx context.Context)
+	RepairNodeGroups(ctx context.Context)
+}
+
+type autoRepairService struct {
+	logger            *logrus.Logger
+	repository        repository.IRepository
+	identityService   IIdentityService
+	computeService    IComputeService
+	nodeGroupsService INodeGroupsService
+	kubernetesService IKubernetesService
+}
+
+func NewAutoRepairService(l *logrus.Logger, r repository.IRepository, i IIdentityService, c IComputeService, ng INodeGroupsService, k IKubernetesService) IAutoRepairService {
+	return &autoRepairService{
+		logger:            l,
+		repository:        r,
+		identityService:   i,
+		computeService:    c,
+		nodeGroupsService: ng,
+		kubernetesService: k,
+	}
+}
+
+// Start runs the auto repair loop until the context is cancelled. It does
+// nothing when auto repair is disabled in the configuration.
+func (ar *autoRepairService) Start(ctx context.Context) {
+	autoRepairConfig := config.GlobalConfig.GetAutoRepairConfig()
+	if !autoRepairConfig.Enabled {
+		ar.logger.Info("auto repair controller is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(autoRepairConfig.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	ar.logger.WithFields(logrus.Fields{
+		"intervalSeconds": autoRepairConfig.IntervalSeconds,
+	}).Info("auto repair controller started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ar.RepairNodeGroups(ctx)
+		}
+	}
+}
+
+func (ar *autoRepairService) RepairNodeGroups(ctx context.Context) {
+	nodeGroups, err := ar.repository.NodeGroups().GetAutoRepairNodeGroups(ctx)
+	if err != nil {
+		ar.logger.WithError(err).Error("failed to get auto repair node groups")
+		return
+	}
+
+	for _, nodeGroup := range nodeGroups {
+		ar.repairNodeGroup(ctx, nodeGroup)
+	}
+}
+
+func (ar *autoRepairService) repairNodeGroup(ctx context.Context, nodeGroup model.NodeGroups) {
+	cluster, err := ar.repository.Cluster().GetClusterByUUID(ctx, nodeGroup.ClusterUUID)
+	if err != nil {
+		ar.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": nodeGroup.ClusterUUID,
+		}).Error("failed to get cluster")
+		return
+	}
+	if cluster.ClusterStatus != ActiveClusterStatus {
+		return
+	}
+
+	token, err := ar.identityService.GetServiceToken(ctx, cluster.ClusterProjectUUID)
+	if err != nil {
+		ar.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to get service token")
+		return
+	}
+
+	members, err := ar.computeService.GetServerGroupMemberList(ctx, token, nodeGroup.NodeGroupUUID)
+	if err != nil {
+		ar.logger.WithError(err).WithFields(logrus.Fields{
+			"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+		}).Error("failed to get server group member list")
+		return
+	}
+
+	// Kubernetes node conditions are optional, if the API is unreachable the
+	// decision is made with the compute status only.
+	var kubernetesNodes map[string]resource.KubernetesNode
+	nodes, err := ar.kubernetesService.GetNodes(ctx, cluster.ClusterUUID)
+	if err != nil {
+		ar.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Warn("failed to get kubernetes nodes, using compute status only")
+	} else {
+		kubernetesNodes = make(map[string]resource.KubernetesNode)
+		for _, node := range nodes {
+			kubernetesNodes[strings.ToLower(node.Metadata.Name)] = node
+		}
+	}
+
+	for _, member := range members.Members {
+		server, err := ar.computeService.GetInstancesDetail(ctx, token, member)
+		if err != nil {
+			ar.logger.WithError(err).WithFields(logrus.Fields{
+				"serverID": member,
+			}).Error("failed to get instance detail")
+			continue
+		}
+
+		reason := ar.getUnhealthyReason(server.OpenstackServers, kubernetesNodes)
+		if reason == "" {
+			continue
+		}
+
+		if !ar.allowRepair(ctx, cluster, nodeGroup) {
+			ar.logger.WithFields(logrus.Fields{
+				"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+				"serverID":      member,
+				"reason":        reason,
+			}).Warn("auto repair rate limit reached, skipping unhealthy node")
+			return
+		}
+
+		ar.replaceNode(ctx, token, cluster, nodeGroup, len(members.Members), server.OpenstackServers, reason)
+
+		// Only one node is replaced per node group in each run.
+		return
+	}
+}
+
+func (ar *autoRepairService) getUnhealthyReason(server resource.OpenstackServer, kubernetesNodes map[string]resource.KubernetesNode) string {
+	grace := time.Duration(config.GlobalConfig.GetAutoRepairConfig().NotReadyGraceMinutes) * time.Minute
+
+	switch server.Status {
+	case ServerStatusError, ServerStatusShutoff:
+		return fmt.Sprintf("server status is %s", server.Status)
+	}
+
+	if kubernetesNodes == nil || time.Since(server.Created) < grace {
+		return ""
+	}
+
+	node, ok := kubernetesNodes[strings.ToLower(server.Name)]
+	if !ok {
+		return "node is not registered in kubernetes"
+	}
+
+	ready, lastTransitionTime := IsKubernetesNodeReady(node)
+	if !ready && time.Since(lastTransitionTime) >= grace {
+		return fmt.Sprintf("node is not ready since %s", lastTransitionTime.Format(time.RFC3339))
+	}
+
+	return ""
+}
+
+// allowRepair reports whether the number of repairs of the node group in the
+// configured window is below the limit. The repairs are counted from the audit
+// log so the limit survives restarts and is shared between replicas.
+func (ar *autoRepairService) allowRepair(ctx context.Context, cluster *model.Cluster, nodeGroup model.NodeGroups) bool {
+	autoRepairConfig := config.GlobalConfig.GetAutoRepairConfig()
+	window := time.Duration(autoRepairConfig.RepairWindowMinutes) * time.Minute
+
+	attempts, err := ar.repository.AuditLog().CountAuditLogs(ctx, cluster.ClusterUUID, fmt.Sprintf(autoRepairStartedEvent, "%", repository.EscapeLikePattern(nodeGroup.NodeGroupName), "%"), time.Now().Add(-window))
+	if err != nil {
+		ar.logger.WithError(err).WithFields(logrus.Fields{
+			"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+		}).Error("failed to count auto repair attempts")
+		return false
+	}
+
+	return attempts < int64(autoRepairConfig.MaxRepairsPerWindow)
+}
+
+func (ar *autoRepairService) replaceNode(ctx context.Context, token string, cluster *model.Cluster, nodeGroup model.NodeGroups, currentCount int, server resource.OpenstackServer, reason string) {
+	ar.createAuditLog(ctx, cluster, fmt.Sprintf(autoRepairStartedEvent, server.Name, nodeGroup.NodeGroupName, reason))
+
+	var addNodeResp resource.AddNodeResponse
+	var err error
+
+	// Add the replacement first when there is room in the node group so the
+	// capacity does not drop, otherwise free a slot by deleting the old node.
+	if currentCount < nodeGroup.NodeGroupMaxSize {
+		addNodeResp, err = ar.nodeGroupsService.AddNode(ctx, token, cluster.ClusterUUID, nodeGroup.NodeGroupUUID)
+		if err == nil {
+			_, err = ar.nodeGroupsService.DeleteUnhealthyNode(ctx, token, cluster.ClusterUUID, nodeGroup.NodeGroupUUID, server.ID)
+		}
+	} else {
+		_, err = ar.nodeGroupsService.DeleteUnhealthyNode(ctx, token, cluster.ClusterUUID, nodeGroup.NodeGroupUUID, server.ID)
+		if err == nil {
+			addNodeResp, err = ar.nodeGroupsService.AddNode(ctx, token, cluster.ClusterUUID, nodeGroup.NodeGroupUUID)
+		}
+	}
+
+	if err != nil {
+		ar.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID":   cluster.ClusterUUID,
+			"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+			"serverID":      server.ID,
+		}).Error("failed to auto repair node")
+		ar.createAuditLog(ctx, cluster, fmt.Sprintf("Auto repair failed for node %s in node group %s", server.Name, nodeGroup.NodeGroupName))
+		return
+	}
+
+	ar.logger.WithFields(logrus.Fields{
+		"clusterUUID":   cluster.ClusterUUID,
+		"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+		"serverID":      server.ID,
+		"newServerID":   addNodeResp.ComputeID,
+		"reason":        reason,
+	}).Info("node auto repaired")
+	ar.createAuditLog(ctx, cluster, fmt.Sprintf("Node %s replaced by %s in node group %s by auto repair", server.Name, addNodeResp.ComputeID, nodeGroup.NodeGroupName))
+}
+
+func (ar *autoRepairService) createAuditLog(ctx context.Context, cluster *model.Cluster, event string) {
+	err := ar.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
+		ClusterUUID: cluster.ClusterUUID,
+		ProjectUUID: cluster.ClusterProjectUUID,
+		Event:       event,
+		CreateDate:  time.Now(),
+	})
+	if err != nil {
+		ar.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to create audit log")
+	}
+}
@@ -13,50 +13,61 @@ import (
 	"github.com/vmindtech/vke/internal/dto/resource"
 )
 
-type ICloudflareService interface {
-	AddDNSRecordToCloudflare(ctx context.Context, loadBalancerIP, loadBalancerSubdomainHash, clusterName string) (resource.AddDNSRecordResponse, error)
-	DeleteDNSRecordFromCloudflare(ctx context.Context, dnsRecordID string) error
-	DeleteDNSRecord(ctx context.Context, recordID string) error
-}
+const (
+	cloudflareEndpoint     = "https://api.cloudflare.com/client/v4/zones"
+	cloudflareListPageSize = 100
+)
 
-type cloudflareService struct {
+type cloudflareDNSProvider struct {
 	logger *logrus.Logger
 	client http.Client
 }
 
-func NewCloudflareService(logger *logrus.Logger) ICloudflareService {
-	return &cloudflareService{
+func NewCloudflareDNSProvider(logger *logrus.Logger) IDNSProvider {
+	return &cloudflareDNSProvider{
 		logger: logger,
 		client: CreateHTTPClient(),
 	}
 }
 
-func (cf *cloudflareService) AddDNSRecordToCloudflare(ctx context.Context, loadBalancerIP, loadBalancerSubdomainHash, clusterName string) (resource.AddDNSRecordResponse, error) {
+func (cf *cloudflareDNSProvider) Name() string {
+	return DNSProviderCloudflare
+}
+
+func (cf *cloudflareDNSProvider) CreateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error) {
+	return cf.sendRecord(ctx, "POST", fmt.Sprintf("%s/%s/dns_records", cloudflareEndpoint, config.GlobalConfig.GetCloudflareConfig().ZoneID), record)
+}
+
+func (cf *cloudflareDNSProvider) UpdateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error) {
+	return cf.sendRecord(ctx, "PUT", fmt.Sprintf("%s/%s/dns_records/%s", cloudflareEndpoint, config.GlobalConfig.GetCloudflareConfig().ZoneID, record.ID), record)
+}
+
+func (cf *cloudflareDNSProvider) sendRecord(ctx context.Context, method, url string, record resource.DNSRecord) (resource.DNSRecord, error) {
 	addDNSRecordCFRequest := &request.AddDNSRecordCFRequest{
-		Content: loadBalancerIP,
-		Name:    fmt.Sprintf("%s.%s", loadBalancerSubdomainHash, config.GlobalConfig.GetCloudflareConfig().Domain),
+		Content: record.Content,
+		Name:    record.Name,
 		Proxied: false,
-		Type:    "A",
-		Comment: clusterName,
+		Type:    record.Type,
+		Comment: record.Comment,
 		Tags:    []string{},
-		TTL:     3600,
+		TTL:     record.TTL,
 	}
 	data, err := json.Marshal(addDNSRecordCFRequest)
 	if err != nil {
 		cf.logger.WithError(err).WithFields(logrus.Fields{
-			"loadBalancerSubdomainHash": loadBalancerSubdomainHash,
-			"clusterName":               clusterName,
+			"name":    record.Name,
+			"comment": record.Comment,
 		}).Error("failed to marshal request")
-		return resource.AddDNSRecordResponse{}, err
+		return resource.DNSRecord{}, err
 	}
 
-	r, err := http.NewRequest("POST", fmt.Sprintf("%s/%s/dns_records", cloudflareEndpoint, config.GlobalConfig.GetCloudflareConfig().ZoneID), bytes.NewBuffer(data))
+	r, err := http.NewRequest(method, url, bytes.NewBuffer(data))
 	if err != nil {
 		cf.logger.WithError(err).WithFields(logrus.Fields{
-			"loadBalancerSubdomainHash": loadBalancerSubdomainHash,
-			"clusterName":               clusterName,
+			"name":    record.Name,
+			"comment": record.Comment,
 		}).Error("failed to create request")
-		return resource.AddDNSRecordResponse{}, err
+		return resource.DNSRecord{}, err
 	}
 	r.Header = make(http.Header)
 	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", config.GlobalConfig.GetCloudflareConfig().CfToken))
@@ -65,20 +76,20 @@ func (cf *cloudflareService) AddDNSRecordToCloudflare(ctx context.Context, loadB
 	resp, err := cf.client.Do(r)
 	if err != nil {
 		cf.logger.WithError(err).WithFields(logrus.Fields{
-			"loadBalancerSubdomainHash": loadBalancerSubdomainHash,
-			"clusterName":               clusterName,
+			"name":    record.Name,
+			"comment": record.Comment,
 		}).Error("failed to send request")
 
-		return resource.AddDNSRecordResponse{}, err
+		return resource.DNSRecord{}, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		cf.logger.WithFields(logrus.Fields{
-			"loadBalancerSubdomainHash": loadBalancerSubdomainHash,
-			"clusterName":               clusterName,
-		}).Errorf("failed to add dns record, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
-		return resource.AddDNSRecordResponse{}, fmt.Errorf("failed to add dns record, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+			"name":    record.Name,
+			"comment": record.Comment,
+		}).Errorf("failed to save dns record, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+		return resource.DNSRecord{}, fmt.Errorf("failed to save dns record, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
 	}
 
 	var respDecoder resource.AddDNSRecordResponse
@@ -86,41 +97,78 @@ func (cf *cloudflareService) AddDNSRecordToCloudflare(ctx context.Context, loadB
 	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
 	if err != nil {
 		cf.logger.WithError(err).WithFields(logrus.Fields{
-			"loadBalancerSubdomainHash": loadBalancerSubdomainHash,
-			"clusterName":               clusterName,
+			"name":    record.Name,
+			"comment": record.Comment,
 		}).Error("failed to decode response")
-		return resource.AddDNSRecordResponse{}, err
+		return resource.DNSRecord{}, err
 	}
 
-	return respDecoder, nil
+	return resource.DNSRecord{
+		Provider: DNSProviderCloudflare,
+		ID:       respDecoder.Result.ID,
+		Name:     respDecoder.Result.Name,
+		Type:     respDecoder.Result.Type,
+		Content:  respDecoder.Result.Content,
+		Comment:  respDecoder.Result.Comment,
+		TTL:      respDecoder.Result.TTL,
+	}, nil
 }
 
-func (cf *cloudflareService) DeleteDNSRecordFromCloudflare(ctx context.Context, dnsRecordID string) error {
-	r, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s/dns_records/%s", cloudflareEndpoint, config.GlobalConfig.GetCloudflareConfig().ZoneID, dnsRecordID), nil)
-	if err != nil {
-		cf.logger.WithError(err).WithField("dnsRecordID", dnsRecordID).Error("failed to create request")
-		return err
+// ListRecords pages through the A and AAAA records of the zone.
+func (cf *cloudflareDNSProvider) ListRecords(ctx context.Context) ([]resource.DNSRecord, error) {
+	records := []resource.DNSRecord{}
+	for page := 1; ; page++ {
+		r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/dns_records?per_page=%d&page=%d", cloudflareEndpoint, config.GlobalConfig.GetCloudflareConfig().ZoneID, cloudflareListPageSize, page), nil)
+		if err != nil {
+			cf.logger.WithError(err).Error("failed to create request")
+			return nil, err
+		}
+		r.Header = make(http.Header)
+		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", config.GlobalConfig.GetCloudflareConfig().CfToken))
+		r.Header.Add("Content-Type", "application/json")
+
+		resp, err := cf.client.Do(r)
+		if err != nil {
+			cf.logger.WithError(err).Error("failed to send request")
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			cf.logger.Errorf("failed to list dns records, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+			return nil, fmt.Errorf("failed to list dns records, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+		}
+
+		var respDecoder resource.ListDNSRecordsResponse
+		err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+		resp.Body.Close()
+		if err != nil {
+			cf.logger.WithError(err).Error("failed to decode response")
+			return nil, err
+		}
+
+		for _, result := range respDecoder.Result {
+			if result.Type != DNSRecordTypeA && result.Type != DNSRecordTypeAAAA {
+				continue
+			}
+			records = append(records, resource.DNSRecord{
+				Provider: DNSProviderCloudflare,
+				ID:       result.ID,
+				Name:     result.Name,
+				Type:     result.Type,
+				Content:  result.Content,
+				Comment:  result.Comment,
+				TTL:      result.TTL,
+			})
+		}
+
+		if page >= respDecoder.ResultInfo.TotalPages {
+			return records, nil
+		}
 	}
-	r.Header = make(http.Header)
-	r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", config.GlobalConfig.GetCloudflareConfig().CfToken))
-	r.Header.Add("Content-Type", "application/json")
-
-	resp, err := cf.client.Do(r)
-	if err != nil {
-		cf.logger.WithError(err).WithField("dnsRecordID", dnsRecordID).Error("failed to send request")
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		cf.logger.WithField("dnsRecordID", dnsRecordID).Errorf("failed to delete dns record, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
-		return fmt.Errorf("failed to delete dns record, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
-	}
-
-	return nil
 }
 
-func (cf *cloudflareService) DeleteDNSRecord(ctx context.Context, recordID string) error {
+func (cf *cloudflareDNSProvider) DeleteRecord(ctx context.Context, recordID string) error {
 	r, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s/dns_records/%s", cloudflareEndpoint, config.GlobalConfig.GetCloudflareConfig().ZoneID, recordID), nil)
 	if err != nil {
 		cf.logger.WithError(err).WithField("recordID", recordID).Error("failed to create request")
@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -29,28 +31,38 @@ type IClusterService interface {
 	CreateKubeConfig(ctx context.Context, authToken string, req request.CreateKubeconfigRequest) (resource.CreateKubeconfigResponse, error)
 	UpdateKubeConfig(ctx context.Context, authToken string, clusterID string, req request.UpdateKubeconfigRequest) (resource.UpdateKubeconfigResponse, error)
 	CreateAuditLog(ctx context.Context, clusterUUID, projectUUID, event string) error
+	ReplaceMaster(ctx context.Context, authToken, clusterID, serverID string) (resource.ReplaceMasterResponse, error)
+	GetControlPlaneDrift(ctx context.Context, authToken, clusterID string) (resource.ControlPlaneDriftReport, error)
+	ReconcileControlPlane(ctx context.Context, authToken, clusterID string) (resource.ControlPlaneDriftReport, error)
+	ReconcileClusterControlPlane(ctx context.Context, token string, cluster *model.Cluster, apply bool) (resource.ControlPlaneDriftReport, error)
 }
 
 type clusterService struct {
-	cloudflareService   ICloudflareService
+	dnsService          IDNSService
 	loadbalancerService ILoadbalancerService
 	networkService      INetworkService
 	computeService      IComputeService
 	nodeGroupsService   INodeGroupsService
 	logger              *logrus.Logger
 	identityService     IIdentityService
+	imageService        IImageService
+	kubernetesService   IKubernetesService
+	ingressService      IIngressService
 	repository          repository.IRepository
 }
 
-func NewClusterService(l *logrus.Logger, cf ICloudflareService, lbc ILoadbalancerService, ns INetworkService, cs IComputeService, ng INodeGroupsService, i IIdentityService, r repository.IRepository) IClusterService {
+func NewClusterService(l *logrus.Logger, dns IDNSService, lbc ILoadbalancerService, ns INetworkService, cs IComputeService, ng INodeGroupsService, i IIdentityService, im IImageService, k IKubernetesService, ig IIngressService, r repository.IRepository) IClusterService {
 	return &clusterService{
-		cloudflareService:   cf,
+		dnsService:          dns,
 		loadbalancerService: lbc,
 		networkService:      ns,
 		computeService:      cs,
 		nodeGroupsService:   ng,
 		logger:              l,
 		identityService:     i,
+		imageService:        im,
+		kubernetesService:   k,
+		ingressService:      ig,
 		repository:          r,
 	}
 }
@@ -65,9 +77,10 @@ const (
 )
 
 const (
-	LoadBalancerStatusActive  = "ACTIVE"
-	LoadBalancerStatusDeleted = "DELETED"
-	LoadBalancerStatusError   = "ERROR"
+	LoadBalancerStatusActive        = "ACTIVE"
+	LoadBalancerStatusDeleted       = "DELETED"
+	LoadBalancerStatusError         = "ERROR"
+	LoadBalancerStatusPendingCreate = "PENDING_CREATE"
 )
 
 const (
@@ -83,12 +96,15 @@ const (
 )
 
 const (
-	MasterServerType = "server"
-	WorkerServerType = "agent"
+	NodeGroupScaleInReject = "reject"
+	NodeGroupScaleInDrain  = "drain"
 )
 
+const DefaultAvailabilityZone = "nova"
+
 const (
-	cloudflareEndpoint = "https://api.cloudflare.com/client/v4/zones"
+	MasterServerType = "server"
+	WorkerServerType = "agent"
 )
 
 func (c *clusterService) CreateAuditLog(ctx context.Context, clusterUUID, projectUUID, event string) error {
@@ -182,6 +198,21 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	clusterUUID := uuid.New().String()
 	clUUID <- clusterUUID
 
+	networkCIDR, err := ResolveClusterNetworkCIDR(req)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate cluster network")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrClusterSubnetInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+
 	subnetIdsJSON, err := json.Marshal(req.SubnetIDs)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
@@ -198,6 +229,209 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		return
 	}
 
+	masterServerGroupPolicy, err := ValidateServerGroupPolicy(req.MasterServerGroupPolicy)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate master server group policy")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrComputeServerGroupPolicyInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+
+	workerServerGroupPolicy, err := ValidateServerGroupPolicy(req.WorkerServerGroupPolicy)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate worker server group policy")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrComputeServerGroupPolicyInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+
+	masterImageRef, err := c.imageService.ValidateImage(ctx, token, req.ProjectID, req.MasterImageRef)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate master image")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrImageValidationFailed, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+
+	workerImageRef, err := c.imageService.ValidateImage(ctx, token, req.ProjectID, req.WorkerImageRef)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate worker image")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrImageValidationFailed, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+
+	err = validateClusterNodeGroups(req.NodeGroups)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate node groups")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrNodeGroupCreateFailed, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+
+	lbSettings, err := ResolveLoadBalancerSettings(req.LoadBalancerSettings)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate load balancer settings")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrLoadBalancerSettingsInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+	lbProvider, err := ResolveLoadBalancerProvider(ctx, c.loadbalancerService, token, req.LoadBalancerProvider, req.LoadBalancerFlavorID, lbSettings)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate load balancer provider")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrLoadBalancerProviderInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+	lbSettingsJSON, err := json.Marshal(lbSettings)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to marshal load balancer settings")
+		c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrLoadBalancerSettingsInvalid, "cluster_creation", err)
+		return
+	}
+
+	customDNSNames, tlsSANs, err := ResolveClusterTLSSANs(req.CustomDNSNames, req.ExtraTLSSANs)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate custom dns names")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrDNSNameInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+	err = c.checkClusterDNSNamesAvailable(ctx, customDNSNames)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate custom dns names")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrDNSNameInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+	appsDNSTarget := ""
+	if req.AppsWildcardDNS != nil {
+		appsDNSTarget = AppsDNSTargetIngress
+		if req.AppsWildcardDNS.Address != "" {
+			appsDNSTarget = req.AppsWildcardDNS.Address
+		}
+	}
+	tlsSANsJSON, err := json.Marshal(tlsSANs)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to marshal tls sans")
+		c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrDNSNameInvalid, "cluster_creation", err)
+		return
+	}
+
+	cni, cniOptions, err := ResolveCNISettings(req.CNI, req.CNIOptions)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate cni")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrClusterCNIInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+	cniOptionsJSON, err := json.Marshal(cniOptions)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to marshal cni options")
+		c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrClusterCNIInvalid, "cluster_creation", err)
+		return
+	}
+
+	err = ValidateUserDataExtensions(req.UserData)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to validate user data")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrUserDataInvalid, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+		return
+	}
+
+	clusterUserDataJSON, err := json.Marshal(req.UserData)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to marshal user data")
+		c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrUserDataInvalid, "cluster_creation", err)
+		return
+	}
+
 	createApplicationCredentialReq, err := c.identityService.CreateApplicationCredential(ctx, clusterUUID, token)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
@@ -243,6 +477,14 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		FloatingIPUUID:               "",
 		ClusterSharedSecurityGroup:   "",
 		ApplicationCredentialID:      createApplicationCredentialReq.Credential.ID,
+		ClusterUserData:              clusterUserDataJSON,
+		ClusterLBSettings:            lbSettingsJSON,
+		ClusterLBProvider:            lbProvider,
+		ClusterLBFlavorID:            req.LoadBalancerFlavorID,
+		ClusterTLSSANs:               tlsSANsJSON,
+		ClusterAppsDNSTarget:         appsDNSTarget,
+		ClusterCNI:                   cni,
+		ClusterCNIOptions:            cniOptionsJSON,
 		ClusterCertificateExpireDate: time.Now().AddDate(0, 0, 365),
 		DeleteState:                  constants.DeleteStateInitial,
 	}
@@ -273,15 +515,100 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		return
 	}
 
+	if req.CreateNetwork != nil {
+		subnetID, err := c.createClusterNetwork(ctx, token, clusterUUID, req.ClusterName, networkCIDR)
+		if err == nil {
+			req.SubnetIDs = []string{subnetID}
+			subnetIdsJSON, err = json.Marshal(req.SubnetIDs)
+		}
+		if err == nil {
+			clusterModel.ClusterSubnets = subnetIdsJSON
+			err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+		}
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create cluster network")
+			c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrNetworkCreateFailed, "cluster_creation", err)
+			err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+				}).Error("failed to create audit log")
+			}
+
+			clusterModel.ClusterStatus = ErrorClusterStatus
+			err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+				}).Error("failed to update cluster")
+			}
+			return
+		}
+	}
+
+	clusterSubnets, err := c.networkService.GetClusterSubnets(ctx, token, req.SubnetIDs)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to get cluster subnets")
+		c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrClusterSubnetInvalid, "cluster_creation", err)
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+
+		clusterModel.ClusterStatus = ErrorClusterStatus
+		err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to update cluster")
+		}
+		return
+	}
+	rke2Network, err := clusterSubnets.ResolveRKE2NetworkSettings(req.PodCIDR, req.ServiceCIDR, req.ClusterDomain, req.AllowedCIDRS)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to resolve cluster network settings")
+		c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrClusterNetworkInvalid, "cluster_creation", err)
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+
+		clusterModel.ClusterStatus = ErrorClusterStatus
+		err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to update cluster")
+		}
+		return
+	}
+	clusterModel.ClusterPodCIDR = rke2Network.ClusterCIDR
+	clusterModel.ClusterServiceCIDR = rke2Network.ServiceCIDR
+	clusterModel.ClusterDomain = rke2Network.ClusterDomain
+	rke2Network.CNI = cni
+	rke2Network.CNIOptions = cniOptions
+
 	floatingIPUUID := ""
 	// Create Load Balancer for masters
 	createLBReq := &request.CreateLoadBalancerRequest{
 		LoadBalancer: request.LoadBalancer{
-			Name:         fmt.Sprintf("%v-lb", req.ClusterName),
-			Description:  fmt.Sprintf("%v-lb", req.ClusterName),
-			AdminStateUp: true,
-			VIPSubnetID:  req.SubnetIDs[0],
-			Provider:     config.GlobalConfig.GetOpenStackApiConfig().LoadbalancerProvider,
+			Name:           fmt.Sprintf("%v-lb", req.ClusterName),
+			Description:    fmt.Sprintf("%v-lb", req.ClusterName),
+			AdminStateUp:   true,
+			VIPSubnetID:    clusterSubnets.VIPSubnetID(),
+			Provider:       lbProvider,
+			FlavorID:       req.LoadBalancerFlavorID,
+			AdditionalVIPs: clusterSubnets.AdditionalVIPs(),
 		},
 	}
 
@@ -367,8 +694,15 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		return
 	}
 	loadbalancerIP := listLBResp.LoadBalancer.VIPAddress
-	// Control plane access type
-	if req.ClusterAPIAccess == "public" {
+	loadbalancerIPv6 := ""
+	for _, vip := range listLBResp.LoadBalancer.AdditionalVIPs {
+		if ip := net.ParseIP(vip.IPAddress); ip != nil && ip.To4() == nil {
+			loadbalancerIPv6 = vip.IPAddress
+			break
+		}
+	}
+	// Control plane access type, IPv6 only clusters are reached on their VIP
+	if req.ClusterAPIAccess == "public" && len(clusterSubnets.IPv4) > 0 {
 		createFloatingIPreq := &request.CreateFloatingIPRequest{
 			FloatingIP: request.FloatingIP{
 				FloatingNetworkID: config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
@@ -538,7 +872,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	createServerGroupReq := &request.CreateServerGroupRequest{
 		ServerGroup: request.ServerGroup{
 			Name:   fmt.Sprintf("%v-master-server-group", req.ClusterName),
-			Policy: "soft-anti-affinity",
+			Policy: masterServerGroupPolicy,
 		},
 	}
 	masterServerGroupResp, err := c.computeService.CreateServerGroup(ctx, token, *createServerGroupReq)
@@ -588,6 +922,8 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		NodeGroupsStatus:       NodeGroupCreatingStatus,
 		NodeGroupsType:         NodeGroupMasterType,
 		NodeGroupSecurityGroup: createMasterSecurityResp.SecurityGroup.ID,
+		ServerGroupPolicy:      masterServerGroupPolicy,
+		NodeImageRef:           masterImageRef,
 		IsHidden:               true,
 		NodeGroupCreateDate:    time.Now(),
 	}
@@ -615,6 +951,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	}
 
 	createServerGroupReq.ServerGroup.Name = fmt.Sprintf("%v-default-worker-server-group", req.ClusterName)
+	createServerGroupReq.ServerGroup.Policy = workerServerGroupPolicy
 	workerServerGroupResp, err := c.computeService.CreateServerGroup(ctx, token, *createServerGroupReq)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
@@ -663,6 +1000,8 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		NodeGroupsStatus:       NodeGroupCreatingStatus,
 		NodeGroupsType:         NodeGroupWorkerType,
 		NodeGroupSecurityGroup: createWorkerSecurityResp.SecurityGroup.ID,
+		ServerGroupPolicy:      workerServerGroupPolicy,
+		NodeImageRef:           workerImageRef,
 		IsHidden:               false,
 		NodeGroupCreateDate:    time.Now(),
 	}
@@ -695,7 +1034,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	rke2InitScript, err := GenerateUserDataFromTemplate("true",
 		MasterServerType,
 		rke2Token,
-		fmt.Sprintf("%s.%s", clusterSubdomainHash, config.GlobalConfig.GetCloudflareConfig().Domain),
+		fmt.Sprintf("%s.%s", clusterSubdomainHash, config.GlobalConfig.GetDNSConfig().Domain),
 		req.KubernetesVersion,
 		req.ClusterName,
 		clusterUUID,
@@ -712,6 +1051,9 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		createApplicationCredentialReq.Credential.Secret,
 		config.GlobalConfig.GetVkeAgentConfig().ClusterAgentVersion,
 		config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+		tlsSANs,
+		rke2Network,
+		[]request.UserDataExtensions{req.UserData},
 	)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
@@ -776,6 +1118,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	}
 
 	for _, allowedCIDR := range req.AllowedCIDRS {
+		createSecurityGroupRuleReq.SecurityGroupRule.Ethertype = SecurityGroupEthertype(allowedCIDR)
 		createSecurityGroupRuleReq.SecurityGroupRule.RemoteIPPrefix = allowedCIDR
 		err = c.networkService.CreateSecurityGroupRuleForIP(ctx, token, *createSecurityGroupRuleReq)
 		if err != nil {
@@ -813,38 +1156,40 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 			RemoteGroupID:   createClusterSharedSecurityResp.SecurityGroup.ID,
 		},
 	}
-	err = c.networkService.CreateSecurityGroupRuleForSG(ctx, token, *createSecurityGroupRuleReqSG)
-	if err != nil {
-		c.logger.WithError(err).WithFields(logrus.Fields{
-			"clusterUUID": clusterUUID,
-		}).Error("failed to create security group rule")
-		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+	ethertypes := []string{"IPv4"}
+	if len(clusterSubnets.IPv6) > 0 {
+		ethertypes = append(ethertypes, "IPv6")
+	}
+	for _, ethertype := range ethertypes {
+		createSecurityGroupRuleReqSG.SecurityGroupRule.Ethertype = ethertype
+		err = c.networkService.CreateSecurityGroupRuleForSG(ctx, token, *createSecurityGroupRuleReqSG)
 		if err != nil {
 			c.logger.WithError(err).WithFields(logrus.Fields{
 				"clusterUUID": clusterUUID,
-			}).Error("failed to create audit log")
-		}
+			}).Error("failed to create security group rule")
+			err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+				}).Error("failed to create audit log")
+			}
 
-		clusterModel.ClusterStatus = ErrorClusterStatus
-		err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
-		if err != nil {
-			c.logger.WithError(err).WithFields(logrus.Fields{
-				"clusterUUID": clusterUUID,
-			}).Error("failed to update cluster")
+			clusterModel.ClusterStatus = ErrorClusterStatus
+			err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+				}).Error("failed to update cluster")
+			}
+			return
 		}
-		return
 	}
-	randSubnetId := GetRandomStringFromArray(req.SubnetIDs)
 	portRequest := &request.CreateNetworkPortRequest{
 		Port: request.Port{
-			NetworkID:    getNetworkIdResp.Subnet.NetworkID,
-			Name:         "PortName",
-			AdminStateUp: true,
-			FixedIps: []request.FixedIp{
-				{
-					SubnetID: randSubnetId,
-				},
-			},
+			NetworkID:      getNetworkIdResp.Subnet.NetworkID,
+			Name:           "PortName",
+			AdminStateUp:   true,
+			FixedIps:       clusterSubnets.FixedIps(),
 			SecurityGroups: []string{createMasterSecurityResp.SecurityGroup.ID, createClusterSharedSecurityResp.SecurityGroup.ID},
 		},
 	}
@@ -875,7 +1220,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	masterRequest := &request.CreateComputeRequest{
 		Server: request.Server{
 			Name:             "ServerName",
-			ImageRef:         config.GlobalConfig.GetImageRefConfig().ImageRef,
+			ImageRef:         masterImageRef,
 			FlavorRef:        req.MasterInstanceFlavorUUID,
 			KeyName:          req.NodeKeyPairName,
 			AvailabilityZone: "nova",
@@ -889,7 +1234,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 					DestinationType:     "volume",
 					DeleteOnTermination: true,
 					SourceType:          "image",
-					UUID:                config.GlobalConfig.GetImageRefConfig().ImageRef,
+					UUID:                masterImageRef,
 					VolumeSize:          50,
 				},
 			},
@@ -959,6 +1304,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		createSecurityGroupRuleReq.SecurityGroupRule.PortRangeMin = "6443"
 		createSecurityGroupRuleReq.SecurityGroupRule.PortRangeMax = "6443"
 		createSecurityGroupRuleReq.SecurityGroupRule.SecurityGroupID = createMasterSecurityResp.SecurityGroup.ID
+		createSecurityGroupRuleReq.SecurityGroupRule.Ethertype = SecurityGroupEthertype(subnetDetails.Subnet.CIDR)
 		createSecurityGroupRuleReq.SecurityGroupRule.RemoteIPPrefix = subnetDetails.Subnet.CIDR
 
 		err = c.networkService.CreateSecurityGroupRuleForIP(ctx, token, *createSecurityGroupRuleReq)
@@ -1038,13 +1384,49 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		}
 	}
 
-	// add DNS record to cloudflare
+	// add DNS record for the cluster endpoint
+
+	dnsRecord, err := c.dnsService.CreateRecord(ctx, fmt.Sprintf("%s.%s", clusterSubdomainHash, config.GlobalConfig.GetDNSConfig().Domain), loadbalancerIP, req.ClusterName)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to add dns record")
+
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+
+		clusterModel.ClusterStatus = ErrorClusterStatus
+		err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to update cluster")
+		}
+		return
+	}
+	// store the records right away so they are cleaned up when a later step
+	// fails and the cluster is deleted
+	clusterModel.ClusterDNSProvider = dnsRecord.Provider
+	clusterModel.ClusterDNSRecordID = dnsRecord.ID
 
-	addDNSResp, err := c.cloudflareService.AddDNSRecordToCloudflare(ctx, loadbalancerIP, clusterSubdomainHash, req.ClusterName)
+	err = c.createClusterDNSRecords(ctx, clusterModel, customDNSNames, loadbalancerIP)
+	if err == nil && loadbalancerIPv6 != "" {
+		// dual-stack clusters get AAAA records for the IPv6 VIP
+		err = c.createClusterDNSRecords(ctx, clusterModel, append([]string{dnsRecord.Name}, customDNSNames...), loadbalancerIPv6)
+	}
+	if err == nil && appsDNSTarget != "" && appsDNSTarget != AppsDNSTargetIngress {
+		// a wildcard pointing at the ingress load balancer is created with it
+		err = c.createClusterDNSRecords(ctx, clusterModel, []string{appsWildcardDNSName(dnsRecord.Name)}, appsDNSTarget)
+	}
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
 			"clusterUUID": clusterUUID,
-		}).Error("failed to add dns record to cloudflare")
+		}).Error("failed to add custom dns records")
+		c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrDNSRecordCreateFailed, "cluster_creation", err)
 
 		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
 		if err != nil {
@@ -1208,16 +1590,8 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		}
 		return
 	}
-	createPoolReq := &request.CreatePoolRequest{
-		Pool: request.Pool{
-			Protocol:     "TCP",
-			AdminStateUp: true,
-			ListenerID:   apiListenerResp.Listener.ID,
-			Name:         fmt.Sprintf("%v-api-pool", req.ClusterName),
-			LBAlgorithm:  "SOURCE_IP_PORT",
-		},
-	}
-	apiPoolResp, err := c.loadbalancerService.CreatePool(ctx, token, *createPoolReq)
+	createPoolReq := loadBalancerPool(lbSettings, fmt.Sprintf("%v-api-pool", req.ClusterName), apiListenerResp.Listener.ID)
+	apiPoolResp, err := c.loadbalancerService.CreatePool(ctx, token, createPoolReq)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
 			"clusterUUID": clusterUUID,
@@ -1260,18 +1634,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		}
 		return
 	}
-	err = c.loadbalancerService.CreateHealthTCPMonitor(ctx, token, request.CreateHealthMonitorTCPRequest{
-		HealthMonitor: request.HealthMonitorTCP{
-			Name:           fmt.Sprintf("%v-api-healthmonitor", req.ClusterName),
-			AdminStateUp:   true,
-			PoolID:         apiPoolResp.Pool.ID,
-			MaxRetries:     "10",
-			Delay:          "10",
-			TimeOut:        "10",
-			Type:           "TCP",
-			MaxRetriesDown: 3,
-		},
-	})
+	err = c.loadbalancerService.CreateHealthHTTPMonitor(ctx, token, apiHealthMonitor(lbSettings, fmt.Sprintf("%v-api-healthmonitor", req.ClusterName), apiPoolResp.Pool.ID))
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
 			"clusterUUID": clusterUUID,
@@ -1316,7 +1679,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		}
 		return
 	}
-	registerPoolResp, err := c.loadbalancerService.CreatePool(ctx, token, *createPoolReq)
+	registerPoolResp, err := c.loadbalancerService.CreatePool(ctx, token, createPoolReq)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
 			"clusterUUID": clusterUUID,
@@ -1359,18 +1722,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		}
 		return
 	}
-	err = c.loadbalancerService.CreateHealthHTTPMonitor(ctx, token, request.CreateHealthMonitorHTTPRequest{
-		HealthMonitor: request.HealthMonitorHTTP{
-			Name:           fmt.Sprintf("%v-register-healthmonitor", req.ClusterName),
-			AdminStateUp:   true,
-			PoolID:         registerPoolResp.Pool.ID,
-			MaxRetries:     "10",
-			Delay:          "30",
-			TimeOut:        "10",
-			Type:           "TCP",
-			MaxRetriesDown: 3,
-		},
-	})
+	err = c.loadbalancerService.CreateHealthTCPMonitor(ctx, token, registerHealthMonitor(lbSettings, fmt.Sprintf("%v-register-healthmonitor", req.ClusterName), registerPoolResp.Pool.ID))
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
 			"clusterUUID": clusterUUID,
@@ -1396,8 +1748,8 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		Member: request.Member{
 			Name:         fmt.Sprintf("%v-master-1", req.ClusterName),
 			AdminStateUp: true,
-			SubnetID:     randSubnetId,
-			Address:      portResp.Port.FixedIps[0].IpAddress,
+			SubnetID:     PrimaryFixedIP(portResp.Port.FixedIps).SubnetID,
+			Address:      PrimaryFixedIP(portResp.Port.FixedIps).IpAddress,
 			ProtocolPort: 6443,
 			Backup:       false,
 		},
@@ -1520,7 +1872,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	rke2InitScript, err = GenerateUserDataFromTemplate("false",
 		MasterServerType,
 		rke2Token,
-		fmt.Sprintf("%s.%s", clusterSubdomainHash, config.GlobalConfig.GetCloudflareConfig().Domain),
+		fmt.Sprintf("%s.%s", clusterSubdomainHash, config.GlobalConfig.GetDNSConfig().Domain),
 		req.KubernetesVersion,
 		req.ClusterName,
 		clusterUUID,
@@ -1537,6 +1889,9 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		"",
 		"",
 		config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+		tlsSANs,
+		rke2Network,
+		[]request.UserDataExtensions{req.UserData},
 	)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
@@ -1611,7 +1966,8 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 
 	//create member for master 02 for api and register pool
 	createMemberReq.Member.Name = fmt.Sprintf("%v-master-2", req.ClusterName)
-	createMemberReq.Member.Address = portResp.Port.FixedIps[0].IpAddress
+	createMemberReq.Member.SubnetID = PrimaryFixedIP(portResp.Port.FixedIps).SubnetID
+	createMemberReq.Member.Address = PrimaryFixedIP(portResp.Port.FixedIps).IpAddress
 	createMemberReq.Member.ProtocolPort = 6443
 	err = c.loadbalancerService.CreateMember(ctx, token, apiPoolResp.Pool.ID, *createMemberReq)
 	if err != nil {
@@ -1729,6 +2085,36 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		}
 		return
 	}
+	err = c.computeService.CheckServerGroupPlacement(ctx, token, masterServerGroupResp.ServerGroup.ID, masterServerGroupPolicy)
+	if errors.Is(err, errServerGroupPlacementTimeout) {
+		// the following steps wait for the nodes, slow builds do not fail here
+		c.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Warn("master servers are still building")
+		err = nil
+	}
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to place master servers")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrComputeAntiAffinityCapacity, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+
+		clusterModel.ClusterStatus = ErrorClusterStatus
+		err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to update cluster")
+		}
+		return
+	}
+
 	masterNodeGroupModel.NodeGroupSecurityGroup = createMasterSecurityResp.SecurityGroup.ID
 	masterNodeGroupModel.NodeGroupsStatus = NodeGroupActiveStatus
 	masterNodeGroupModel.NodeGroupUpdateDate = time.Now()
@@ -1780,7 +2166,8 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 
 	//create member for master 03 for api and register pool
 	createMemberReq.Member.Name = fmt.Sprintf("%v-master-3", req.ClusterName)
-	createMemberReq.Member.Address = portResp.Port.FixedIps[0].IpAddress
+	createMemberReq.Member.SubnetID = PrimaryFixedIP(portResp.Port.FixedIps).SubnetID
+	createMemberReq.Member.Address = PrimaryFixedIP(portResp.Port.FixedIps).IpAddress
 	createMemberReq.Member.ProtocolPort = 6443
 	err = c.loadbalancerService.CreateMember(ctx, token, apiPoolResp.Pool.ID, *createMemberReq)
 	if err != nil {
@@ -1876,7 +2263,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	rke2WorkerInitScript, err := GenerateUserDataFromTemplate("false",
 		WorkerServerType,
 		rke2Token,
-		fmt.Sprintf("%s.%s", clusterSubdomainHash, config.GlobalConfig.GetCloudflareConfig().Domain),
+		fmt.Sprintf("%s.%s", clusterSubdomainHash, config.GlobalConfig.GetDNSConfig().Domain),
 		req.KubernetesVersion,
 		req.ClusterName,
 		clusterUUID,
@@ -1893,6 +2280,9 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		"",
 		"",
 		config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+		nil,
+		rke2Network,
+		[]request.UserDataExtensions{req.UserData},
 	)
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
@@ -1918,7 +2308,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	WorkerRequest := &request.CreateComputeRequest{
 		Server: request.Server{
 			Name:             "ServerName",
-			ImageRef:         config.GlobalConfig.GetImageRefConfig().ImageRef,
+			ImageRef:         workerImageRef,
 			FlavorRef:        req.WorkerInstanceFlavorUUID,
 			KeyName:          req.NodeKeyPairName,
 			AvailabilityZone: "nova",
@@ -1932,7 +2322,7 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 					DestinationType:     "volume",
 					DeleteOnTermination: true,
 					SourceType:          "image",
-					UUID:                config.GlobalConfig.GetImageRefConfig().ImageRef,
+					UUID:                workerImageRef,
 					VolumeSize:          req.WorkerDiskSizeGB,
 				},
 			},
@@ -1993,17 +2383,47 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 				c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrAuditLogCreateFailed, "cluster_creation", err)
 			}
 
-			clusterModel.ClusterStatus = ErrorClusterStatus
-			err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
-			if err != nil {
-				c.logger.WithError(err).WithFields(logrus.Fields{
-					"clusterUUID": clusterUUID,
-				}).Error("failed to update cluster")
-				c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrDatabaseQueryFailed, "cluster_creation", err)
-			}
-			return
+			clusterModel.ClusterStatus = ErrorClusterStatus
+			err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+				}).Error("failed to update cluster")
+				c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrDatabaseQueryFailed, "cluster_creation", err)
+			}
+			return
+		}
+	}
+	err = c.computeService.CheckServerGroupPlacement(ctx, token, workerServerGroupResp.ServerGroup.ID, workerServerGroupPolicy)
+	if errors.Is(err, errServerGroupPlacementTimeout) {
+		// the following steps wait for the nodes, slow builds do not fail here
+		c.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Warn("worker servers are still building")
+		err = nil
+	}
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to place worker servers")
+		c.logClusterErrorWithDetails(ctx, clusterUUID, constants.ErrComputeAntiAffinityCapacity, "cluster_creation", err.Error())
+		err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create audit log")
+		}
+
+		clusterModel.ClusterStatus = ErrorClusterStatus
+		err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to update cluster")
 		}
+		return
 	}
+
 	workerNodeGroupModel.NodeGroupLabels = nodeGroupLabelsJSON
 	workerNodeGroupModel.NodeGroupsStatus = NodeGroupActiveStatus
 	workerNodeGroupModel.NodeGroupSecurityGroup = createWorkerSecurityResp.SecurityGroup.ID
@@ -2031,6 +2451,40 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		return
 	}
 
+	if len(req.NodeGroups) > 0 {
+		// CreateNodeGroup reads the join settings of the cluster, store them
+		// before the cluster is marked active.
+		clusterModel.ClusterRegisterToken = rke2Token
+		clusterModel.ClusterAgentToken = rke2AgentToken
+		clusterModel.ClusterEndpoint = dnsRecord.Name
+		clusterModel.ClusterSharedSecurityGroup = ClusterSharedSecurityGroupUUID
+		err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+		if err == nil {
+			err = c.createClusterNodeGroups(ctx, token, clusterUUID, req.NodeGroups)
+		}
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Error("failed to create node groups")
+			c.logClusterErrorFiltered(ctx, clusterUUID, constants.ErrNodeGroupCreateFailed, "cluster_creation", err)
+			err = c.CreateAuditLog(ctx, clusterUUID, req.ProjectID, "Cluster Create Failed")
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+				}).Error("failed to create audit log")
+			}
+
+			clusterModel.ClusterStatus = ErrorClusterStatus
+			err = c.repository.Cluster().UpdateCluster(ctx, clusterModel)
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+				}).Error("failed to update cluster")
+			}
+			return
+		}
+	}
+
 	clusterModel = &model.Cluster{
 		ClusterUUID:                clusterUUID,
 		ClusterName:                req.ClusterName,
@@ -2045,8 +2499,15 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 		ClusterAPIAccess:           req.ClusterAPIAccess,
 		FloatingIPUUID:             floatingIPUUID,
 		ClusterSharedSecurityGroup: ClusterSharedSecurityGroupUUID,
-		ClusterEndpoint:            addDNSResp.Result.Name,
-		ClusterCloudflareRecordID:  addDNSResp.Result.ID,
+		ClusterEndpoint:            dnsRecord.Name,
+		ClusterDNSProvider:         dnsRecord.Provider,
+		ClusterDNSRecordID:         dnsRecord.ID,
+		ClusterDNSRecords:          clusterModel.ClusterDNSRecords,
+		ClusterPodCIDR:             clusterModel.ClusterPodCIDR,
+		ClusterServiceCIDR:         clusterModel.ClusterServiceCIDR,
+		ClusterDomain:              clusterModel.ClusterDomain,
+		ClusterCNI:                 clusterModel.ClusterCNI,
+		ClusterCNIOptions:          clusterModel.ClusterCNIOptions,
 	}
 	err = c.CheckKubeConfig(ctx, clusterUUID)
 	if err != nil {
@@ -2084,6 +2545,59 @@ func (c *clusterService) CreateCluster(ctx context.Context, authToken string, re
 	}
 }
 
+// validateClusterNodeGroups checks the node groups of a create request before
+// any resource is provisioned.
+func validateClusterNodeGroups(nodeGroups []request.CreateNodeGroupRequest) error {
+	names := map[string]bool{}
+	for _, nodeGroup := range nodeGroups {
+		if nodeGroup.NodeGroupName == "" {
+			return fmt.Errorf("node group name is required")
+		}
+		if len(nodeGroup.NodeGroupName) > 20 {
+			return fmt.Errorf("node group name %s is too long", nodeGroup.NodeGroupName)
+		}
+		if nodeGroup.NodeGroupName == "default-wg" || names[nodeGroup.NodeGroupName] {
+			return fmt.Errorf("node group name %s is duplicated", nodeGroup.NodeGroupName)
+		}
+		names[nodeGroup.NodeGroupName] = true
+
+		if nodeGroup.NodeFlavorUUID == "" {
+			return fmt.Errorf("node flavor of node group %s is required", nodeGroup.NodeGroupName)
+		}
+		if nodeGroup.NodeDiskSize < 20 {
+			return fmt.Errorf("node disk size of node group %s must be at least 20 GB", nodeGroup.NodeGroupName)
+		}
+		if nodeGroup.NodeGroupMinSize < 0 || nodeGroup.NodeGroupMaxSize < 1 || nodeGroup.NodeGroupMinSize > nodeGroup.NodeGroupMaxSize {
+			return fmt.Errorf("invalid min and max size of node group %s", nodeGroup.NodeGroupName)
+		}
+		for _, taint := range nodeGroup.NodeGroupTaints {
+			if !strings.Contains(taint, "=") {
+				return fmt.Errorf("invalid taint format in node group %s", nodeGroup.NodeGroupName)
+			}
+		}
+		if err := validateAvailabilityZones(nodeGroup.AvailabilityZones); err != nil {
+			return err
+		}
+		if err := validateDataVolumes(nodeGroup.DataVolumes); err != nil {
+			return err
+		}
+		if err := ValidateUserDataExtensions(nodeGroup.UserData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *clusterService) createClusterNodeGroups(ctx context.Context, token, clusterUUID string, nodeGroups []request.CreateNodeGroupRequest) error {
+	for _, nodeGroup := range nodeGroups {
+		_, err := c.nodeGroupsService.CreateNodeGroup(ctx, token, clusterUUID, nodeGroup)
+		if err != nil {
+			return fmt.Errorf("failed to create node group %s: %w", nodeGroup.NodeGroupName, err)
+		}
+	}
+	return nil
+}
+
 func (c *clusterService) GetCluster(ctx context.Context, authToken, clusterID string) (resource.GetClusterResponse, error) {
 	token := strings.Clone(authToken)
 
@@ -2184,6 +2698,17 @@ func (c *clusterService) GetClusterDetails(ctx context.Context, authToken, clust
 		ClusterEndpoint:              cluster.ClusterEndpoint,
 		ClusterAPIAccess:             cluster.ClusterAPIAccess,
 		ClusterCertificateExpireDate: cluster.ClusterCertificateExpireDate,
+		ClusterLoadBalancerSettings:  getLoadBalancerSettings(cluster.ClusterLBSettings),
+		ClusterLoadBalancerProvider:  clusterLoadBalancerProvider(cluster),
+		ClusterLoadBalancerFlavorID:  cluster.ClusterLBFlavorID,
+		ClusterDNSNames:              clusterDNSNames(cluster),
+		ClusterTLSSANs:               getClusterTLSSANs(cluster.ClusterTLSSANs),
+		ClusterAppsDNSTarget:         cluster.ClusterAppsDNSTarget,
+		ClusterPodCIDR:               cluster.ClusterPodCIDR,
+		ClusterServiceCIDR:           cluster.ClusterServiceCIDR,
+		ClusterDomain:                cluster.ClusterDomain,
+		ClusterCNI:                   clusterCNI(cluster),
+		ClusterCNIOptions:            getCNIOptions(cluster.ClusterCNIOptions),
 	}
 
 	nodeGroups, err := c.nodeGroupsService.GetNodeGroupsByClusterUUID(ctx, cluster.ClusterUUID)
@@ -2384,6 +2909,23 @@ func (c *clusterService) DestroyCluster(ctx context.Context, authToken string, c
 		fallthrough
 
 	case constants.DeleteStateSecurityGroups:
+		if err := c.deleteClusterNetwork(ctx, token, cluster); err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to delete cluster network")
+			c.logClusterErrorFiltered(ctx, cluster.ClusterUUID, constants.ErrNetworkDeleteFailed, "cluster_deletion", err)
+			if err := c.CreateAuditLog(ctx, cluster.ClusterUUID, cluster.ClusterProjectUUID, "Cluster Delete Failed: cluster network remains, delete the cluster again to retry"); err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": cluster.ClusterUUID,
+				}).Error("failed to create audit log")
+			}
+			return
+		}
+		cluster.DeleteState = constants.DeleteStateNetwork
+		c.updateClusterDeleteState(ctx, cluster)
+		fallthrough
+
+	case constants.DeleteStateNetwork:
 		if err := c.deleteApplicationCredentials(ctx, token, cluster); err != nil {
 			c.logger.WithError(err).WithFields(logrus.Fields{
 				"clusterUUID": cluster.ClusterUUID,
@@ -2427,6 +2969,14 @@ func (c *clusterService) updateClusterDeleteState(ctx context.Context, cluster *
 
 func (c *clusterService) deleteLoadBalancerComponents(ctx context.Context, authToken string, cluster *model.Cluster) error {
 	token := strings.Clone(authToken)
+	err := c.ingressService.DeleteClusterIngressLoadBalancer(ctx, token, cluster.ClusterUUID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to delete ingress load balancer")
+		return err
+	}
+
 	getLoadBalancer, err := c.repository.Resources().GetResourceByClusterUUID(ctx, cluster.ClusterUUID, "load_balancer")
 	if err != nil {
 		c.logger.WithError(err).WithFields(logrus.Fields{
@@ -2439,53 +2989,59 @@ func (c *clusterService) deleteLoadBalancerComponents(ctx context.Context, authT
 		return nil
 	}
 
-	pools, err := c.loadbalancerService.GetLoadBalancerPools(ctx, token, getLoadBalancer[0].ResourceUUID)
+	return deleteLoadBalancerWithComponents(ctx, c.logger, c.loadbalancerService, token, cluster.ClusterUUID, getLoadBalancer[0].ResourceUUID)
+}
+
+// deleteLoadBalancerWithComponents deletes the pools and listeners of the load
+// balancer before the load balancer itself.
+func deleteLoadBalancerWithComponents(ctx context.Context, logger *logrus.Logger, lbs ILoadbalancerService, token, clusterUUID, loadBalancerID string) error {
+	pools, err := lbs.GetLoadBalancerPools(ctx, token, loadBalancerID)
 	if err != nil {
 		if strings.Contains(err.Error(), "404") {
-			c.logger.WithFields(logrus.Fields{
-				"clusterUUID": cluster.ClusterUUID,
+			logger.WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
 			}).Info("loadbalancer not found, skipping deletion")
 			return nil
 		}
-		c.logger.WithError(err).WithFields(logrus.Fields{
-			"clusterUUID": cluster.ClusterUUID,
+		logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
 		}).Error("failed to get load balancer pools")
 		return err
 	}
 
 	for _, pool := range pools.Pools {
-		err = c.loadbalancerService.DeleteLoadbalancerPools(ctx, token, pool)
+		err = lbs.DeleteLoadbalancerPools(ctx, token, pool)
 		if err != nil {
-			c.logger.WithError(err).WithFields(logrus.Fields{
-				"clusterUUID": cluster.ClusterUUID,
+			logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
 				"poolID":      pool,
 			}).Error("failed to delete pool")
 			return err
 		}
 	}
 
-	listeners, err := c.loadbalancerService.GetLoadBalancerListeners(ctx, token, getLoadBalancer[0].ResourceUUID)
+	listeners, err := lbs.GetLoadBalancerListeners(ctx, token, loadBalancerID)
 	if err != nil {
-		c.logger.WithError(err).WithFields(logrus.Fields{
-			"clusterUUID": cluster.ClusterUUID,
+		logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
 		}).Error("failed to get load balancer listeners")
 		return err
 	}
 
 	for _, listener := range listeners.Listeners {
-		err = c.loadbalancerService.DeleteLoadbalancerListeners(ctx, token, listener)
+		err = lbs.DeleteLoadbalancerListeners(ctx, token, listener)
 		if err != nil {
-			c.logger.WithError(err).WithFields(logrus.Fields{
-				"clusterUUID": cluster.ClusterUUID,
+			logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
 				"listenerID":  listener,
 			}).Error("failed to delete listener")
 			return err
 		}
 		// Wait for listener deletion
-		err = c.loadbalancerService.CheckLoadBalancerDeletingListeners(ctx, token, listener)
+		err = lbs.CheckLoadBalancerDeletingListeners(ctx, token, listener)
 		if err != nil {
-			c.logger.WithError(err).WithFields(logrus.Fields{
-				"clusterUUID": cluster.ClusterUUID,
+			logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
 				"listenerID":  listener,
 			}).Error("failed to check listener deletion status")
 			return err
@@ -2495,23 +3051,23 @@ func (c *clusterService) deleteLoadBalancerComponents(ctx context.Context, authT
 	// Finally delete the loadbalancer
 	maxRetries := 10
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := c.loadbalancerService.DeleteLoadbalancer(ctx, token, getLoadBalancer[0].ResourceUUID)
+		err := lbs.DeleteLoadbalancer(ctx, token, loadBalancerID)
 		if err == nil {
 			return nil
 		}
 
 		if attempt == maxRetries {
-			c.logger.WithError(err).WithFields(logrus.Fields{
-				"clusterUUID":      cluster.ClusterUUID,
-				"loadbalancerUUID": getLoadBalancer[0].ResourceUUID,
+			logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID":      clusterUUID,
+				"loadbalancerUUID": loadBalancerID,
 				"attempt":          attempt,
 			}).Error("failed to delete load balancer after all retries")
 			return err
 		}
 
-		c.logger.WithFields(logrus.Fields{
-			"clusterUUID":      cluster.ClusterUUID,
-			"loadbalancerUUID": getLoadBalancer[0].ResourceUUID,
+		logger.WithFields(logrus.Fields{
+			"clusterUUID":      clusterUUID,
+			"loadbalancerUUID": loadBalancerID,
 			"attempt":          attempt,
 		}).Warn("retrying load balancer deletion")
 
@@ -2521,30 +3077,105 @@ func (c *clusterService) deleteLoadBalancerComponents(ctx context.Context, authT
 	return nil
 }
 
-func (c *clusterService) deleteDNSRecord(ctx context.Context, cluster *model.Cluster) error {
-	if cluster.ClusterCloudflareRecordID == "" {
+// checkClusterDNSNamesAvailable rejects custom DNS names which are already
+// used by the endpoint, the records or the custom names of a cluster which is
+// not deleted.
+func (c *clusterService) checkClusterDNSNamesAvailable(ctx context.Context, names []string) error {
+	if len(names) == 0 {
 		return nil
 	}
 
+	clusters, err := c.repository.Cluster().GetClusters(ctx)
+	if err != nil {
+		return err
+	}
+
+	usedNames := make(map[string]bool)
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.ClusterStatus == DeletedClusterStatus {
+			continue
+		}
+		usedNames[strings.ToLower(cluster.ClusterEndpoint)] = true
+		for _, record := range getClusterDNSRecords(cluster.ClusterDNSRecords) {
+			usedNames[strings.ToLower(strings.TrimSuffix(record.Name, "."))] = true
+		}
+		for _, san := range getClusterTLSSANs(cluster.ClusterTLSSANs) {
+			usedNames[san] = true
+		}
+	}
+
+	for _, name := range names {
+		if usedNames[name] {
+			return fmt.Errorf("%s: %s is already used by another cluster", constants.ErrDNSNameInvalid, name)
+		}
+	}
+	return nil
+}
+
+// createClusterDNSRecords creates a record for each custom DNS name and keeps
+// the created ones on the cluster model, also when a later one fails.
+func (c *clusterService) createClusterDNSRecords(ctx context.Context, clusterModel *model.Cluster, names []string, address string) error {
+	records := getClusterDNSRecords(clusterModel.ClusterDNSRecords)
+	var createErr error
+	for _, name := range names {
+		record, err := c.dnsService.CreateRecord(ctx, name, address, clusterModel.ClusterName)
+		if err != nil {
+			createErr = err
+			break
+		}
+		records = append(records, record)
+	}
+
+	recordsJSON, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	clusterModel.ClusterDNSRecords = recordsJSON
+
+	return createErr
+}
+
+func (c *clusterService) deleteDNSRecord(ctx context.Context, cluster *model.Cluster) error {
+	records := getClusterDNSRecords(cluster.ClusterDNSRecords)
+	if cluster.ClusterDNSRecordID != "" {
+		records = append([]resource.DNSRecord{{
+			Provider: cluster.ClusterDNSProvider,
+			ID:       cluster.ClusterDNSRecordID,
+		}}, records...)
+	}
+
+	var lastErr error
+	for _, record := range records {
+		err := c.deleteDNSRecordWithRetry(ctx, cluster.ClusterUUID, record)
+		if err != nil {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+func (c *clusterService) deleteDNSRecordWithRetry(ctx context.Context, clusterUUID string, record resource.DNSRecord) error {
 	maxRetries := 3
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := c.cloudflareService.DeleteDNSRecord(ctx, cluster.ClusterCloudflareRecordID)
+		err := c.dnsService.DeleteRecord(ctx, record.Provider, record.ID)
 		if err == nil {
 			return nil
 		}
 
 		if attempt == maxRetries {
 			c.logger.WithError(err).WithFields(logrus.Fields{
-				"clusterUUID": cluster.ClusterUUID,
-				"recordID":    cluster.ClusterCloudflareRecordID,
+				"clusterUUID": clusterUUID,
+				"recordID":    record.ID,
 				"attempt":     attempt,
 			}).Error("failed to delete DNS record after all retries")
 			return err
 		}
 
 		c.logger.WithFields(logrus.Fields{
-			"clusterUUID": cluster.ClusterUUID,
-			"recordID":    cluster.ClusterCloudflareRecordID,
+			"clusterUUID": clusterUUID,
+			"recordID":    record.ID,
 			"attempt":     attempt,
 		}).Warn("retrying DNS record deletion")
 
@@ -2691,6 +3322,26 @@ func (c *clusterService) deleteNodeGroups(ctx context.Context, authToken string,
 		}
 	}
 
+	// Ports on additional node group networks are created before the servers,
+	// so they are not removed together with the servers.
+	getNetworkPorts, err := c.repository.Resources().GetResourceByClusterUUID(ctx, cluster.ClusterUUID, NetworkPortResourceType)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to get network ports")
+		return err
+	}
+	for _, networkPort := range getNetworkPorts {
+		err = c.networkService.DeleteNetworkPort(ctx, token, networkPort.ResourceUUID)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+				"portID":      networkPort.ResourceUUID,
+			}).Error("failed to delete network port")
+			return err
+		}
+	}
+
 	for _, nodeGroup := range nodeGroup {
 		nodeGroup.NodeGroupsStatus = constants.DeletedNodeGroupStatus
 		nodeGroup.NodeGroupDeleteDate = time.Now()
@@ -2798,6 +3449,176 @@ func (c *clusterService) deleteSecurityGroups(ctx context.Context, authToken str
 	return nil
 }
 
+// createClusterNetwork creates a network, a subnet and a router uplinked to
+// PUBLIC_NETWORK_ID for the cluster and returns the subnet id. Every resource
+// is tracked as soon as it exists so that a failed creation is cleaned up by
+// the cluster deletion.
+func (c *clusterService) createClusterNetwork(ctx context.Context, authToken, clusterUUID, clusterName, cidr string) (string, error) {
+	token := strings.Clone(authToken)
+
+	networkResp, err := c.networkService.CreateNetwork(ctx, token, request.CreateNetworkRequest{
+		Network: request.Network{
+			Name:         fmt.Sprintf("%v-network", clusterName),
+			AdminStateUp: true,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	err = c.repository.Resources().CreateResource(ctx, &model.Resource{
+		ClusterUUID:  clusterUUID,
+		ResourceType: "network",
+		ResourceUUID: networkResp.Network.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	subnetResp, err := c.networkService.CreateSubnet(ctx, token, request.CreateSubnetRequest{
+		Subnet: request.Subnet{
+			Name:           fmt.Sprintf("%v-subnet", clusterName),
+			NetworkID:      networkResp.Network.ID,
+			IPVersion:      4,
+			CIDR:           cidr,
+			EnableDHCP:     true,
+			DNSNameservers: config.GlobalConfig.GetClusterNetworkConfig().DNSNameservers,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	err = c.repository.Resources().CreateResource(ctx, &model.Resource{
+		ClusterUUID:  clusterUUID,
+		ResourceType: "subnet",
+		ResourceUUID: subnetResp.Subnet.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	routerResp, err := c.networkService.CreateRouter(ctx, token, request.CreateRouterRequest{
+		Router: request.Router{
+			Name:         fmt.Sprintf("%v-router", clusterName),
+			AdminStateUp: true,
+			ExternalGatewayInfo: request.ExternalGatewayInfo{
+				NetworkID: config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	err = c.repository.Resources().CreateResource(ctx, &model.Resource{
+		ClusterUUID:  clusterUUID,
+		ResourceType: "router",
+		ResourceUUID: routerResp.Router.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	err = c.networkService.AddRouterInterface(ctx, token, routerResp.Router.ID, subnetResp.Subnet.ID)
+	if err != nil {
+		return "", err
+	}
+
+	c.logger.WithFields(logrus.Fields{
+		"clusterUUID": clusterUUID,
+		"networkID":   networkResp.Network.ID,
+		"subnetID":    subnetResp.Subnet.ID,
+		"routerID":    routerResp.Router.ID,
+	}).Info("created cluster network")
+
+	return subnetResp.Subnet.ID, nil
+}
+
+// deleteClusterNetwork removes the routers, subnets and networks vke created
+// for the cluster. Clusters using their own subnets have none of them. Deleted
+// resources are dropped from the resources table, so a retry only handles the
+// remaining ones.
+func (c *clusterService) deleteClusterNetwork(ctx context.Context, authToken string, cluster *model.Cluster) error {
+	token := strings.Clone(authToken)
+
+	maxRetries := 3
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		routers, err := c.repository.Resources().GetResourceByClusterUUID(ctx, cluster.ClusterUUID, "router")
+		if err != nil {
+			return err
+		}
+		subnets, err := c.repository.Resources().GetResourceByClusterUUID(ctx, cluster.ClusterUUID, "subnet")
+		if err != nil {
+			return err
+		}
+		networks, err := c.repository.Resources().GetResourceByClusterUUID(ctx, cluster.ClusterUUID, "network")
+		if err != nil {
+			return err
+		}
+		if len(routers) == 0 && len(subnets) == 0 && len(networks) == 0 {
+			return nil
+		}
+
+		// ports of the deleted servers and load balancers may still be
+		// detaching from the subnets
+		time.Sleep(time.Duration(attempt) * 5 * time.Second)
+
+		err = c.deleteClusterNetworkResources(ctx, token, cluster, routers, subnets, networks)
+		if err == nil {
+			return nil
+		}
+
+		if attempt == maxRetries {
+			return fmt.Errorf("failed to delete cluster network after %d attempts, last error: %v", maxRetries, err)
+		}
+
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+			"attempt":     attempt,
+		}).Warn("retrying cluster network deletion")
+	}
+
+	return nil
+}
+
+func (c *clusterService) deleteClusterNetworkResources(ctx context.Context, token string, cluster *model.Cluster, routers, subnets, networks []model.Resource) error {
+	for _, router := range routers {
+		for _, subnet := range subnets {
+			err := c.networkService.RemoveRouterInterface(ctx, token, router.ResourceUUID, subnet.ResourceUUID)
+			if err != nil && !strings.Contains(err.Error(), "404") {
+				return err
+			}
+		}
+		err := c.networkService.DeleteRouter(ctx, token, router.ResourceUUID)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			return err
+		}
+		if err = c.repository.Resources().DeleteResource(ctx, cluster.ClusterUUID, "router", router.ResourceUUID); err != nil {
+			return err
+		}
+	}
+
+	for _, subnet := range subnets {
+		err := c.networkService.DeleteSubnet(ctx, token, subnet.ResourceUUID)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			return err
+		}
+		if err = c.repository.Resources().DeleteResource(ctx, cluster.ClusterUUID, "subnet", subnet.ResourceUUID); err != nil {
+			return err
+		}
+	}
+
+	for _, network := range networks {
+		err := c.networkService.DeleteNetwork(ctx, token, network.ResourceUUID)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			return err
+		}
+		if err = c.repository.Resources().DeleteResource(ctx, cluster.ClusterUUID, "network", network.ResourceUUID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (c *clusterService) deleteApplicationCredentials(ctx context.Context, authToken string, cluster *model.Cluster) error {
 	token := strings.Clone(authToken)
 
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/pkg/constants"
+	"gorm.io/datatypes"
+)
+
+const (
+	CNICanal  = "canal"
+	CNICalico = "calico"
+	CNICilium = "cilium"
+	// CNINone deploys no CNI, the nodes stay NotReady until one is installed.
+	CNINone = "none"
+
+	// DefaultCNI is the CNI RKE2 deploys when none is selected.
+	DefaultCNI = CNICanal
+
+	minCNIMTU = 1280
+	maxCNIMTU = 9000
+)
+
+// ResolveCNISettings validates the requested CNI and its options. An empty
+// CNI stays empty so RKE2 deploys DefaultCNI without the vke-agent being told.
+func ResolveCNISettings(cni string, options *request.CNIOptions) (string, resource.CNIOptions, error) {
+	cni = strings.ToLower(cni)
+	switch cni {
+	case "", CNICanal, CNICalico, CNICilium, CNINone:
+	default:
+		return "", resource.CNIOptions{}, fmt.Errorf("%s: cni must be one of %s, %s, %s or %s", constants.ErrClusterCNIInvalid, CNICanal, CNICalico, CNICilium, CNINone)
+	}
+
+	cniOptions := resource.CNIOptions{}
+	if options == nil {
+		return cni, cniOptions, nil
+	}
+	if cni == CNINone {
+		return "", resource.CNIOptions{}, fmt.Errorf("%s: cni options require a cni", constants.ErrClusterCNIInvalid)
+	}
+	if options.KubeProxyReplacement && cni != CNICilium {
+		return "", resource.CNIOptions{}, fmt.Errorf("%s: kube-proxy replacement is only available with %s", constants.ErrClusterCNIInvalid, CNICilium)
+	}
+	if options.MTU != 0 && (options.MTU < minCNIMTU || options.MTU > maxCNIMTU) {
+		return "", resource.CNIOptions{}, fmt.Errorf("%s: mtu must be between %d and %d", constants.ErrClusterCNIInvalid, minCNIMTU, maxCNIMTU)
+	}
+	cniOptions.KubeProxyReplacement = options.KubeProxyReplacement
+	cniOptions.MTU = options.MTU
+
+	return cni, cniOptions, nil
+}
+
+// getCNIOptions returns the stored CNI options of a cluster.
+func getCNIOptions(optionsJSON datatypes.JSON) resource.CNIOptions {
+	options := resource.CNIOptions{}
+	if optionsJSON == nil {
+		return options
+	}
+	_ = json.Unmarshal(optionsJSON, &options)
+	return options
+}
+
+// clusterCNI returns the CNI of a cluster, clusters created without a CNI or
+// before the CNI was stored run DefaultCNI.
+func clusterCNI(cluster *model.Cluster) string {
+	if cluster.ClusterCNI != "" {
+		return cluster.ClusterCNI
+	}
+	return DefaultCNI
+}
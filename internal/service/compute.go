@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httputil"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vmindtech/vke/config"
@@ -25,26 +28,50 @@ type IComputeService interface {
 	DeleteServerGroup(ctx context.Context, authToken, clusterServerGroupUUID string) error
 	GetCountOfServerFromServerGroup(ctx context.Context, authToken, serverGroupID, projectUUID string) (int, error)
 	GetInstances(ctx context.Context, authToken, nodeGroupUUID string) ([]resource.Servers, error)
+	GetInstancesDetail(ctx context.Context, authToken, id string) (resource.OpenstacServersResponse, error)
 	GetClusterFlavor(ctx context.Context, authToken string, clusterUUID string) ([]resource.Flavor, error)
 	DeleteCompute(ctx context.Context, authToken, serverID string) error
 	GetServerGroupMemberList(ctx context.Context, authToken, ServerGroupID string) (resource.GetServerGroupMemberListResponse, error)
 	GetServerGroup(ctx context.Context, authToken string, serverGroupID string) (resource.GetServerGroupResponse, error)
 	DeleteServer(ctx context.Context, authToken string, serverID string) error
+	CheckServerGroupPlacement(ctx context.Context, authToken, serverGroupID, policy string) error
+	GetServerVolumes(ctx context.Context, authToken, serverID string) ([]string, error)
+	GetServerVolumeAttachments(ctx context.Context, authToken, serverID string) ([]resource.VolumeAttachment, error)
+	DeleteVolume(ctx context.Context, authToken, volumeID string) error
 }
 
+const (
+	ServerGroupPolicyAffinity         = "affinity"
+	ServerGroupPolicyAntiAffinity     = "anti-affinity"
+	ServerGroupPolicySoftAffinity     = "soft-affinity"
+	ServerGroupPolicySoftAntiAffinity = "soft-anti-affinity"
+	DefaultServerGroupPolicy          = ServerGroupPolicySoftAntiAffinity
+)
+
+const (
+	// Soft policies are available since 2.15, the single policy field since 2.64.
+	novaSoftPolicyMicroVersion  = "2.15"
+	novaPolicyFieldMicroVersion = "2.64"
+	noValidHostFault            = "No valid host"
+	// delete_on_termination is returned in volume attachments since 2.79.
+	novaAttachmentDeleteFlagMicroVersion = "2.79"
+)
+
 type computeService struct {
-	logger          *logrus.Logger
-	identityService IIdentityService
-	repository      repository.IRepository
-	client          http.Client
+	logger            *logrus.Logger
+	identityService   IIdentityService
+	kubernetesService IKubernetesService
+	repository        repository.IRepository
+	client            http.Client
 }
 
-func NewComputeService(l *logrus.Logger, i IIdentityService, repository repository.IRepository) IComputeService {
+func NewComputeService(l *logrus.Logger, i IIdentityService, k IKubernetesService, repository repository.IRepository) IComputeService {
 	return &computeService{
-		logger:          l,
-		identityService: i,
-		repository:      repository,
-		client:          CreateHTTPClient(),
+		logger:            l,
+		identityService:   i,
+		kubernetesService: k,
+		repository:        repository,
+		client:            CreateHTTPClient(),
 	}
 }
 
@@ -93,6 +120,10 @@ func (cs *computeService) CreateCompute(ctx context.Context, authToken string, r
 
 func (cs *computeService) CreateServerGroup(ctx context.Context, authToken string, req request.CreateServerGroupRequest) (resource.ServerGroupResponse, error) {
 	token := strings.Clone(authToken)
+	if !IsMicroVersionSupported(config.GlobalConfig.GetOpenStackApiConfig().NovaMicroVersion, novaPolicyFieldMicroVersion) {
+		req.ServerGroup.Policies = []string{req.ServerGroup.Policy}
+		req.ServerGroup.Policy = ""
+	}
 	data, err := json.Marshal(req)
 	if err != nil {
 		cs.logger.WithError(err).Error("failed to marshal request")
@@ -395,20 +426,43 @@ func (cs *computeService) GetInstances(ctx context.Context, authToken, nodeGroup
 		CurrentNodes:     count,
 		NodeGroupsStatus: nodeGroup.NodeGroupsStatus,
 	})
+	kubernetesNodes := map[string]resource.KubernetesNode{}
+	nodes, kubernetesErr := cs.kubernetesService.GetNodes(ctx, nodeGroup.ClusterUUID)
+	if kubernetesErr != nil {
+		cs.logger.WithError(kubernetesErr).WithFields(logrus.Fields{
+			"clusterUUID": nodeGroup.ClusterUUID,
+		}).Warn("failed to get kubernetes nodes, listing servers without kubernetes status")
+	}
+	for _, node := range nodes {
+		kubernetesNodes[strings.ToLower(node.Metadata.Name)] = node
+	}
+
 	var intanceDetail resource.OpenstacServersResponse
 	var responseData []resource.Servers
 	for _, member := range data.ServerGroup.Members {
 		intanceDetail, err = cs.GetInstancesDetail(ctx, authToken, member)
+		volumes, volumeErr := cs.GetServerVolumeAttachments(ctx, authToken, member)
+		if volumeErr != nil {
+			cs.logger.WithError(volumeErr).WithFields(logrus.Fields{
+				"serverID": member,
+			}).Warn("failed to get volume attachments")
+		}
 		for _, data := range respNodeGroup {
 
 			responseData = append(responseData, resource.Servers{
-				ClusterUUID:   nodeGroup.ClusterUUID,
-				Id:            "openstack:///" + intanceDetail.OpenstackServers.ID,
-				NodeGroupUUID: nodeGroup.NodeGroupUUID,
-				MinSize:       data.NodeGroupMinSize,
-				MaxSize:       data.NodeGroupMaxSize,
-				Flavor:        data.NodeFlavorUUID,
-				Status:        intanceDetail.OpenstackServers.Status,
+				ClusterUUID:      nodeGroup.ClusterUUID,
+				Id:               "openstack:///" + intanceDetail.OpenstackServers.ID,
+				NodeGroupUUID:    nodeGroup.NodeGroupUUID,
+				MinSize:          data.NodeGroupMinSize,
+				MaxSize:          data.NodeGroupMaxSize,
+				Flavor:           data.NodeFlavorUUID,
+				Status:           intanceDetail.OpenstackServers.Status,
+				Volumes:          volumes,
+				Name:             intanceDetail.OpenstackServers.Name,
+				FixedIPs:         serverFixedIPs(intanceDetail.OpenstackServers),
+				AvailabilityZone: intanceDetail.OpenstackServers.AvailabilityZone,
+				CreatedAt:        intanceDetail.OpenstackServers.Created,
+				KubernetesNode:   serverKubernetesNode(kubernetesNodes, intanceDetail.OpenstackServers.Name),
 			})
 
 		}
@@ -420,6 +474,43 @@ func (cs *computeService) GetInstances(ctx context.Context, authToken, nodeGroup
 
 	return responseData, nil
 }
+
+func serverFixedIPs(server resource.OpenstackServer) []string {
+	fixedIPs := []string{}
+	for _, addresses := range server.Addresses {
+		for _, address := range addresses {
+			if address.Type == "" || address.Type == "fixed" {
+				fixedIPs = append(fixedIPs, address.Addr)
+			}
+		}
+	}
+	return fixedIPs
+}
+
+func serverKubernetesNode(kubernetesNodes map[string]resource.KubernetesNode, serverName string) *resource.ServerKubernetesNode {
+	node, ok := kubernetesNodes[strings.ToLower(serverName)]
+	if !ok {
+		return nil
+	}
+
+	kubernetesNode := &resource.ServerKubernetesNode{
+		Name:           node.Metadata.Name,
+		Ready:          KubernetesConditionUnknown,
+		KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		Allocatable:    node.Status.Allocatable,
+		Taints:         node.Spec.Taints,
+		Cordoned:       node.Spec.Unschedulable,
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == KubernetesNodeReadyCondition {
+			kubernetesNode.Ready = condition.Status
+			kubernetesNode.ReadyReason = condition.Reason
+		}
+	}
+
+	return kubernetesNode
+}
+
 func (cs *computeService) GetInstancesDetail(ctx context.Context, authToken, id string) (resource.OpenstacServersResponse, error) {
 	token := strings.Clone(authToken)
 	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().ComputeEndpoint, constants.ComputePath, id), nil)
@@ -608,6 +699,20 @@ func (cs *computeService) DeleteServer(ctx context.Context, authToken string, se
 }
 
 func (cs *computeService) GetServerVolumes(ctx context.Context, authToken, serverID string) ([]string, error) {
+	attachments, err := cs.GetServerVolumeAttachments(ctx, authToken, serverID)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make([]string, 0)
+	for _, attachment := range attachments {
+		volumes = append(volumes, attachment.VolumeID)
+	}
+
+	return volumes, nil
+}
+
+func (cs *computeService) GetServerVolumeAttachments(ctx context.Context, authToken, serverID string) ([]resource.VolumeAttachment, error) {
 	token := strings.Clone(authToken)
 	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s/os-volume_attachments", config.GlobalConfig.GetEndpointsConfig().ComputeEndpoint, constants.ComputePath, serverID), nil)
 	if err != nil {
@@ -615,6 +720,10 @@ func (cs *computeService) GetServerVolumes(ctx context.Context, authToken, serve
 	}
 	r.Header = make(http.Header)
 	r.Header.Add("X-Auth-Token", token)
+	novaMicroVersion := config.GlobalConfig.GetOpenStackApiConfig().NovaMicroVersion
+	if IsMicroVersionSupported(novaMicroVersion, novaAttachmentDeleteFlagMicroVersion) {
+		r.Header.Add("x-openstack-nova-api-version", novaMicroVersion)
+	}
 
 	resp, err := cs.client.Do(r)
 	if err != nil {
@@ -626,22 +735,12 @@ func (cs *computeService) GetServerVolumes(ctx context.Context, authToken, serve
 		return nil, fmt.Errorf("failed to get volumes, status code: %v", resp.StatusCode)
 	}
 
-	var result struct {
-		VolumeAttachments []struct {
-			VolumeID string `json:"volumeId"`
-		} `json:"volumeAttachments"`
-	}
-
+	var result resource.VolumeAttachmentsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
 	}
 
-	volumes := make([]string, 0)
-	for _, attachment := range result.VolumeAttachments {
-		volumes = append(volumes, attachment.VolumeID)
-	}
-
-	return volumes, nil
+	return result.VolumeAttachments, nil
 }
 
 func (cs *computeService) DeleteVolume(ctx context.Context, authToken, volumeID string) error {
@@ -665,3 +764,113 @@ func (cs *computeService) DeleteVolume(ctx context.Context, authToken, volumeID
 
 	return nil
 }
+
+// ValidateServerGroupPolicy returns the default policy for an empty value and
+// checks that the policy is supported by the configured nova microversion.
+func ValidateServerGroupPolicy(policy string) (string, error) {
+	if policy == "" {
+		return DefaultServerGroupPolicy, nil
+	}
+
+	switch policy {
+	case ServerGroupPolicyAffinity, ServerGroupPolicyAntiAffinity:
+		return policy, nil
+	case ServerGroupPolicySoftAffinity, ServerGroupPolicySoftAntiAffinity:
+		novaMicroVersion := config.GlobalConfig.GetOpenStackApiConfig().NovaMicroVersion
+		if !IsMicroVersionSupported(novaMicroVersion, novaSoftPolicyMicroVersion) {
+			return "", fmt.Errorf("%s: %s requires nova microversion %s or later, configured version is %s", constants.ErrComputeServerGroupPolicyInvalid, policy, novaSoftPolicyMicroVersion, novaMicroVersion)
+		}
+		return policy, nil
+	}
+
+	return "", fmt.Errorf("%s: %s, allowed values are %s, %s, %s, %s", constants.ErrComputeServerGroupPolicyInvalid, policy,
+		ServerGroupPolicyAffinity, ServerGroupPolicyAntiAffinity, ServerGroupPolicySoftAffinity, ServerGroupPolicySoftAntiAffinity)
+}
+
+// IsMicroVersionSupported reports whether the configured microversion, e.g.
+// "2.88", is equal to or later than the required one.
+func IsMicroVersionSupported(configured, required string) bool {
+	configuredMajor, configuredMinor, ok := parseMicroVersion(configured)
+	if !ok {
+		return false
+	}
+	requiredMajor, requiredMinor, _ := parseMicroVersion(required)
+
+	if configuredMajor != requiredMajor {
+		return configuredMajor > requiredMajor
+	}
+	return configuredMinor >= requiredMinor
+}
+
+func parseMicroVersion(version string) (int, int, bool) {
+	parts := strings.Split(strings.TrimSpace(version), ".")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// errServerGroupPlacementTimeout is returned by CheckServerGroupPlacement when
+// members of the server group are still building after the wait.
+var errServerGroupPlacementTimeout = errors.New(constants.ErrComputePlacementTimeout)
+
+// CheckServerGroupPlacement waits until the members of a strict anti-affinity
+// server group are scheduled and reports a capacity error when nova could not
+// find a separate host for a member. Other policies are not checked.
+func (cs *computeService) CheckServerGroupPlacement(ctx context.Context, authToken, serverGroupID, policy string) error {
+	if policy != ServerGroupPolicyAntiAffinity {
+		return nil
+	}
+
+	token := strings.Clone(authToken)
+	waitIterator := 0
+	waitSeconds := 10
+	for {
+		if waitIterator >= 30 {
+			cs.logger.WithFields(logrus.Fields{
+				"serverGroupID": serverGroupID,
+			}).Warn("timed out waiting for server group members to be scheduled")
+			return errServerGroupPlacementTimeout
+		}
+		time.Sleep(time.Duration(waitSeconds) * time.Second)
+		waitIterator++
+
+		members, err := cs.GetServerGroupMemberList(ctx, token, serverGroupID)
+		if err != nil {
+			return err
+		}
+
+		building := false
+		for _, member := range members.Members {
+			server, err := cs.GetInstancesDetail(ctx, token, member)
+			if err != nil {
+				return err
+			}
+			if server.OpenstackServers.Status == "BUILD" {
+				building = true
+				continue
+			}
+			if server.OpenstackServers.Status == ServerStatusError && strings.Contains(server.OpenstackServers.Fault.Message, noValidHostFault) {
+				cs.logger.WithFields(logrus.Fields{
+					"serverGroupID": serverGroupID,
+					"serverID":      member,
+					"fault":         server.OpenstackServers.Fault.Message,
+				}).Error("failed to schedule server with strict anti-affinity policy")
+				return fmt.Errorf("%s: server %s could not be scheduled because every member of an anti-affinity server group must run on a different compute host and there are not enough hosts available for %d servers, use soft-anti-affinity or reduce the node group size",
+					constants.ErrComputeAntiAffinityCapacity, server.OpenstackServers.Name, len(members.Members))
+			}
+		}
+
+		if !building {
+			return nil
+		}
+	}
+}
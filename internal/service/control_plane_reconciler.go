@@ -0,0 +1,361 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/internal/repository"
+)
+
+type IControlPlaneReconcilerService interface {
+	Start(ctx context.Context)
+	ReconcileClusters(ctx context.Context)
+}
+
+type controlPlaneReconcilerService struct {
+	logger          *logrus.Logger
+	repository      repository.IRepository
+	identityService IIdentityService
+	clusterService  IClusterService
+}
+
+func NewControlPlaneReconcilerService(l *logrus.Logger, r repository.IRepository, i IIdentityService, c IClusterService) IControlPlaneReconcilerService {
+	return &controlPlaneReconcilerService{
+		logger:          l,
+		repository:      r,
+		identityService: i,
+		clusterService:  c,
+	}
+}
+
+// Start runs the control plane reconcile loop until the context is cancelled.
+// It does nothing when reconciliation is disabled in the configuration.
+func (cpr *controlPlaneReconcilerService) Start(ctx context.Context) {
+	reconcileConfig := config.GlobalConfig.GetControlPlaneReconcileConfig()
+	if !reconcileConfig.Enabled {
+		cpr.logger.Info("control plane reconciler is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(reconcileConfig.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	cpr.logger.WithFields(logrus.Fields{
+		"intervalSeconds": reconcileConfig.IntervalSeconds,
+	}).Info("control plane reconciler started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cpr.ReconcileClusters(ctx)
+		}
+	}
+}
+
+func (cpr *controlPlaneReconcilerService) ReconcileClusters(ctx context.Context) {
+	clusters, err := cpr.repository.Cluster().GetClustersByStatus(ctx, ActiveClusterStatus)
+	if err != nil {
+		cpr.logger.WithError(err).Error("failed to get active clusters")
+		return
+	}
+
+	for i := range clusters {
+		cluster := &clusters[i]
+		if cluster.ClusterLoadbalancerUUID == "" {
+			continue
+		}
+
+		token, err := cpr.identityService.GetServiceToken(ctx, cluster.ClusterProjectUUID)
+		if err != nil {
+			cpr.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to get service token")
+			continue
+		}
+
+		report, err := cpr.clusterService.ReconcileClusterControlPlane(ctx, token, cluster, true)
+		if err != nil {
+			cpr.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to reconcile control plane load balancer members")
+			continue
+		}
+		if !report.InSync {
+			cpr.logger.WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Info("control plane load balancer members reconciled")
+		}
+	}
+}
+
+// GetControlPlaneDrift compares the api and register pool members with the
+// masters of the cluster without changing anything.
+func (c *clusterService) GetControlPlaneDrift(ctx context.Context, authToken, clusterID string) (resource.ControlPlaneDriftReport, error) {
+	token := strings.Clone(authToken)
+
+	cluster, err := c.getControlPlaneCluster(ctx, token, clusterID)
+	if err != nil {
+		return resource.ControlPlaneDriftReport{}, err
+	}
+
+	return c.ReconcileClusterControlPlane(ctx, token, cluster, false)
+}
+
+// ReconcileControlPlane adds the missing masters to the api and register pools
+// and removes the members that no longer belong to a master.
+func (c *clusterService) ReconcileControlPlane(ctx context.Context, authToken, clusterID string) (resource.ControlPlaneDriftReport, error) {
+	token := strings.Clone(authToken)
+
+	cluster, err := c.getControlPlaneCluster(ctx, token, clusterID)
+	if err != nil {
+		return resource.ControlPlaneDriftReport{}, err
+	}
+	if cluster.ClusterStatus != ActiveClusterStatus {
+		c.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to reconcile control plane, cluster is not active")
+		return resource.ControlPlaneDriftReport{}, fmt.Errorf("failed to reconcile control plane, cluster is not active")
+	}
+
+	return c.ReconcileClusterControlPlane(ctx, token, cluster, true)
+}
+
+func (c *clusterService) getControlPlaneCluster(ctx context.Context, token, clusterID string) (*model.Cluster, error) {
+	cluster, err := c.repository.Cluster().GetClusterByUUID(ctx, clusterID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to get cluster")
+		return nil, err
+	}
+	if cluster == nil || cluster.ClusterProjectUUID == "" {
+		c.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to get cluster")
+		return nil, fmt.Errorf("failed to get cluster")
+	}
+
+	err = c.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to check auth token")
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
+// ReconcileClusterControlPlane builds the drift report of the control plane
+// load balancer and repairs it when apply is set. A member is expected for
+// every master on both pools, members are matched by address and port since
+// their names do not follow the server names.
+func (c *clusterService) ReconcileClusterControlPlane(ctx context.Context, token string, cluster *model.Cluster, apply bool) (resource.ControlPlaneDriftReport, error) {
+	report := resource.ControlPlaneDriftReport{
+		ClusterUUID:      cluster.ClusterUUID,
+		LoadBalancerUUID: cluster.ClusterLoadbalancerUUID,
+		InSync:           true,
+		Pools:            []resource.ControlPlanePoolDrift{},
+		CheckDate:        time.Now(),
+	}
+
+	expected, err := c.getMasterMembers(ctx, token, cluster)
+	if err != nil {
+		return resource.ControlPlaneDriftReport{}, err
+	}
+	// An empty list would remove every member, which is never the intent.
+	if len(expected) == 0 {
+		return resource.ControlPlaneDriftReport{}, fmt.Errorf("failed to find the addresses of the masters of cluster %s", cluster.ClusterUUID)
+	}
+
+	masterPools, err := c.getMasterPools(ctx, token, cluster.ClusterLoadbalancerUUID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to get master load balancer pools")
+		return resource.ControlPlaneDriftReport{}, err
+	}
+
+	for poolID, protocolPort := range masterPools {
+		poolMembers, err := c.loadbalancerService.ListMembers(ctx, token, poolID)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"poolID": poolID,
+			}).Error("failed to list pool members")
+			return resource.ControlPlaneDriftReport{}, err
+		}
+
+		drift := resource.ControlPlanePoolDrift{
+			PoolID:         poolID,
+			ProtocolPort:   protocolPort,
+			MissingMembers: []resource.ControlPlaneMember{},
+			StaleMembers:   []resource.ControlPlaneMember{},
+		}
+		present := map[string]bool{}
+		for _, member := range poolMembers.Members {
+			if _, ok := expected[member.Address]; ok && member.ProtocolPort == protocolPort {
+				present[member.Address] = true
+				continue
+			}
+			drift.StaleMembers = append(drift.StaleMembers, resource.ControlPlaneMember{
+				MemberID: member.ID,
+				Name:     member.Name,
+				Address:  member.Address,
+				SubnetID: member.SubnetID,
+			})
+		}
+		for address, member := range expected {
+			if !present[address] {
+				drift.MissingMembers = append(drift.MissingMembers, member)
+			}
+		}
+
+		if len(drift.MissingMembers) > 0 || len(drift.StaleMembers) > 0 {
+			report.InSync = false
+		}
+		report.Pools = append(report.Pools, drift)
+	}
+
+	if !apply || report.InSync {
+		return report, nil
+	}
+
+	for _, drift := range report.Pools {
+		// Missing members are added first so the pool never loses capacity.
+		for _, member := range drift.MissingMembers {
+			_, err = c.loadbalancerService.CheckLoadBalancerStatus(ctx, token, cluster.ClusterLoadbalancerUUID)
+			if err != nil {
+				return report, err
+			}
+			err = c.loadbalancerService.CreateMember(ctx, token, drift.PoolID, request.AddMemberRequest{
+				Member: request.Member{
+					Name:         member.Name,
+					AdminStateUp: true,
+					SubnetID:     member.SubnetID,
+					Address:      member.Address,
+					ProtocolPort: drift.ProtocolPort,
+				},
+			})
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"poolID":  drift.PoolID,
+					"address": member.Address,
+				}).Error("failed to create pool member")
+				return report, err
+			}
+		}
+		for _, member := range drift.StaleMembers {
+			_, err = c.loadbalancerService.CheckLoadBalancerStatus(ctx, token, cluster.ClusterLoadbalancerUUID)
+			if err != nil {
+				return report, err
+			}
+			err = c.loadbalancerService.DeleteMember(ctx, token, drift.PoolID, member.MemberID)
+			if err != nil {
+				c.logger.WithError(err).WithFields(logrus.Fields{
+					"poolID":   drift.PoolID,
+					"memberID": member.MemberID,
+				}).Error("failed to delete pool member")
+				return report, err
+			}
+		}
+	}
+	report.Reconciled = true
+
+	err = c.CreateAuditLog(ctx, cluster.ClusterUUID, cluster.ClusterProjectUUID, "Control plane load balancer members reconciled")
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to create audit log")
+	}
+
+	return report, nil
+}
+
+// getMasterMembers returns the expected pool member of every master keyed by
+// its address on the cluster subnets.
+func (c *clusterService) getMasterMembers(ctx context.Context, token string, cluster *model.Cluster) (map[string]resource.ControlPlaneMember, error) {
+	masterNodeGroups, err := c.repository.NodeGroups().GetNodeGroupsByClusterUUID(ctx, cluster.ClusterUUID, NodeGroupMasterType, "")
+	if err != nil || len(masterNodeGroups) == 0 {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to get master node group")
+		return nil, fmt.Errorf("failed to get master node group")
+	}
+
+	subnetIDs := []string{}
+	err = json.Unmarshal(cluster.ClusterSubnets, &subnetIDs)
+	if err != nil || len(subnetIDs) == 0 {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to unmarshal cluster subnets")
+		return nil, fmt.Errorf("failed to get subnet ids")
+	}
+	subnetCIDRs := getSubnetCIDRs(ctx, c.logger, c.networkService, token, subnetIDs)
+
+	servers, err := c.computeService.GetInstances(ctx, token, masterNodeGroups[0].NodeGroupUUID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to get master instances")
+		return nil, err
+	}
+
+	members := map[string]resource.ControlPlaneMember{}
+	for _, server := range servers {
+		address := clusterSubnetAddress(subnetCIDRs, server.FixedIPs)
+		if address == "" {
+			continue
+		}
+		members[address] = resource.ControlPlaneMember{
+			Name:     server.Name,
+			Address:  address,
+			SubnetID: memberSubnetID(subnetCIDRs, subnetIDs, address),
+		}
+	}
+
+	return members, nil
+}
+
+// clusterSubnetAddress returns the first address on one of the cluster
+// subnets, or on any subnet when the subnets could not be read. IPv4 addresses
+// are preferred like for the load balancer VIPs.
+func clusterSubnetAddress(subnetCIDRs map[string]string, addresses []string) string {
+	clusterAddress := ""
+	for _, address := range addresses {
+		ip := net.ParseIP(address)
+		if ip == nil || !onClusterSubnet(subnetCIDRs, ip) {
+			continue
+		}
+		if ip.To4() != nil {
+			return address
+		}
+		if clusterAddress == "" {
+			clusterAddress = address
+		}
+	}
+	return clusterAddress
+}
+
+func onClusterSubnet(subnetCIDRs map[string]string, ip net.IP) bool {
+	if len(subnetCIDRs) == 0 {
+		return true
+	}
+	for _, cidr := range subnetCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
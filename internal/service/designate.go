@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/pkg/constants"
+)
+
+const designateListPageSize = 100
+
+type designateDNSProvider struct {
+	logger          *logrus.Logger
+	client          http.Client
+	identityService IIdentityService
+}
+
+// NewDesignateDNSProvider manages recordsets in DESIGNATE_ZONE_ID with a token
+// of the service account scoped to DESIGNATE_PROJECT_ID, the project owning
+// the zone.
+func NewDesignateDNSProvider(logger *logrus.Logger, i IIdentityService) IDNSProvider {
+	return &designateDNSProvider{
+		logger:          logger,
+		client:          CreateHTTPClient(),
+		identityService: i,
+	}
+}
+
+func (d *designateDNSProvider) Name() string {
+	return DNSProviderDesignate
+}
+
+func (d *designateDNSProvider) CreateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error) {
+	createReq := request.CreateRecordSetRequest{
+		Name:        fqdn(record.Name),
+		Type:        record.Type,
+		Records:     []string{record.Content},
+		TTL:         record.TTL,
+		Description: record.Comment,
+	}
+	data, err := json.Marshal(createReq)
+	if err != nil {
+		d.logger.WithError(err).WithField("name", record.Name).Error("failed to marshal request")
+		return resource.DNSRecord{}, err
+	}
+
+	return d.sendRecordSet(ctx, "POST", d.recordSetsURL(), data)
+}
+
+func (d *designateDNSProvider) UpdateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error) {
+	updateReq := request.UpdateRecordSetRequest{
+		Records:     []string{record.Content},
+		TTL:         record.TTL,
+		Description: record.Comment,
+	}
+	data, err := json.Marshal(updateReq)
+	if err != nil {
+		d.logger.WithError(err).WithField("recordID", record.ID).Error("failed to marshal request")
+		return resource.DNSRecord{}, err
+	}
+
+	return d.sendRecordSet(ctx, "PUT", fmt.Sprintf("%s/%s", d.recordSetsURL(), record.ID), data)
+}
+
+func (d *designateDNSProvider) sendRecordSet(ctx context.Context, method, url string, data []byte) (resource.DNSRecord, error) {
+	token, err := d.getToken(ctx)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+
+	r, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	if err != nil {
+		d.logger.WithError(err).Error("failed to create request")
+		return resource.DNSRecord{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+	r.Header.Add("Content-Type", "application/json")
+
+	resp, err := d.client.Do(r)
+	if err != nil {
+		d.logger.WithError(err).Error("failed to send request")
+		return resource.DNSRecord{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusAccepted {
+		d.logger.Errorf("failed to save recordset, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+		return resource.DNSRecord{}, fmt.Errorf("failed to save recordset, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.RecordSetResponse
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		d.logger.WithError(err).Error("failed to decode response")
+		return resource.DNSRecord{}, err
+	}
+
+	return recordSetToDNSRecord(respDecoder), nil
+}
+
+func (d *designateDNSProvider) DeleteRecord(ctx context.Context, recordID string) error {
+	token, err := d.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	r, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s", d.recordSetsURL(), recordID), nil)
+	if err != nil {
+		d.logger.WithError(err).WithField("recordID", recordID).Error("failed to create request")
+		return err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	resp, err := d.client.Do(r)
+	if err != nil {
+		d.logger.WithError(err).WithField("recordID", recordID).Error("failed to send request")
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent {
+		d.logger.WithField("recordID", recordID).Errorf("failed to delete recordset, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+		return fmt.Errorf("failed to delete recordset, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
+
+// ListRecords follows the next links of the recordsets of the zone and
+// returns the A and AAAA recordsets.
+func (d *designateDNSProvider) ListRecords(ctx context.Context) ([]resource.DNSRecord, error) {
+	token, err := d.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []resource.DNSRecord{}
+	url := fmt.Sprintf("%s?limit=%d", d.recordSetsURL(), designateListPageSize)
+	for url != "" {
+		r, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			d.logger.WithError(err).Error("failed to create request")
+			return nil, err
+		}
+		r.Header = make(http.Header)
+		r.Header.Add("X-Auth-Token", token)
+
+		resp, err := d.client.Do(r)
+		if err != nil {
+			d.logger.WithError(err).Error("failed to send request")
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			d.logger.Errorf("failed to list recordsets, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+			return nil, fmt.Errorf("failed to list recordsets, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+		}
+
+		var respDecoder resource.ListRecordSetsResponse
+		err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+		resp.Body.Close()
+		if err != nil {
+			d.logger.WithError(err).Error("failed to decode response")
+			return nil, err
+		}
+
+		for _, recordSet := range respDecoder.RecordSets {
+			if recordSet.Type != DNSRecordTypeA && recordSet.Type != DNSRecordTypeAAAA {
+				continue
+			}
+			records = append(records, recordSetToDNSRecord(recordSet))
+		}
+		url = respDecoder.Links.Next
+	}
+
+	return records, nil
+}
+
+func (d *designateDNSProvider) recordSetsURL() string {
+	return fmt.Sprintf("%s/%s/%s/recordsets", config.GlobalConfig.GetEndpointsConfig().DNSEndpoint, constants.DNSZonesPath, config.GlobalConfig.GetDNSConfig().DesignateZoneID)
+}
+
+func (d *designateDNSProvider) getToken(ctx context.Context) (string, error) {
+	token, err := d.identityService.GetServiceToken(ctx, config.GlobalConfig.GetDNSConfig().DesignateProjectID)
+	if err != nil {
+		d.logger.WithError(err).Error("failed to get designate token")
+		return "", err
+	}
+	return token, nil
+}
+
+func recordSetToDNSRecord(recordSet resource.RecordSetResponse) resource.DNSRecord {
+	record := resource.DNSRecord{
+		Provider: DNSProviderDesignate,
+		ID:       recordSet.ID,
+		Name:     strings.TrimSuffix(recordSet.Name, "."),
+		Type:     recordSet.Type,
+		Comment:  recordSet.Description,
+		TTL:      recordSet.TTL,
+	}
+	if len(recordSet.Records) > 0 {
+		record.Content = recordSet.Records[0]
+	}
+	return record
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
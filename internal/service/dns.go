@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/pkg/constants"
+	"gorm.io/datatypes"
+)
+
+const (
+	DNSProviderCloudflare = "cloudflare"
+	DNSProviderDesignate  = "designate"
+	DNSProviderRFC2136    = "rfc2136"
+
+	DNSRecordTypeA    = "A"
+	DNSRecordTypeAAAA = "AAAA"
+
+	// AppsDNSTargetIngress points the apps wildcard record of a cluster at
+	// its managed ingress load balancer.
+	AppsDNSTargetIngress = "ingress"
+)
+
+var dnsLabelPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// IDNSProvider is implemented by each DNS backend. Record IDs are opaque to
+// the callers and only meaningful to the provider which returned them.
+type IDNSProvider interface {
+	Name() string
+	CreateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error)
+	UpdateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error)
+	DeleteRecord(ctx context.Context, recordID string) error
+	ListRecords(ctx context.Context) ([]resource.DNSRecord, error)
+}
+
+type IDNSService interface {
+	CreateRecord(ctx context.Context, name, address, comment string) (resource.DNSRecord, error)
+	UpdateRecord(ctx context.Context, provider, recordID, name, address, comment string) (resource.DNSRecord, error)
+	DeleteRecord(ctx context.Context, provider, recordID string) error
+	ListRecords(ctx context.Context) (string, []resource.DNSRecord, error)
+}
+
+type dnsService struct {
+	logger    *logrus.Logger
+	providers map[string]IDNSProvider
+}
+
+func NewDNSService(logger *logrus.Logger, providers ...IDNSProvider) IDNSService {
+	d := &dnsService{
+		logger:    logger,
+		providers: make(map[string]IDNSProvider),
+	}
+	for _, provider := range providers {
+		d.providers[provider.Name()] = provider
+	}
+	return d
+}
+
+// CreateRecord creates an A or AAAA record, depending on the address, with the
+// configured DNS_PROVIDER.
+func (d *dnsService) CreateRecord(ctx context.Context, name, address, comment string) (resource.DNSRecord, error) {
+	provider, err := d.getProvider(config.GlobalConfig.GetDNSConfig().Provider)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+
+	recordType, err := dnsRecordType(address)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+
+	record, err := provider.CreateRecord(ctx, resource.DNSRecord{
+		Name:    name,
+		Type:    recordType,
+		Content: address,
+		Comment: comment,
+		TTL:     config.GlobalConfig.GetDNSConfig().TTL,
+	})
+	if err != nil {
+		d.logger.WithError(err).WithFields(logrus.Fields{
+			"provider": provider.Name(),
+			"name":     name,
+		}).Error("failed to create dns record")
+		return resource.DNSRecord{}, err
+	}
+	record.Provider = provider.Name()
+
+	return record, nil
+}
+
+// UpdateRecord updates a record with the provider which created it, records
+// stored before the provider was tracked belong to cloudflare.
+func (d *dnsService) UpdateRecord(ctx context.Context, providerName, recordID, name, address, comment string) (resource.DNSRecord, error) {
+	provider, err := d.getProvider(providerName)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+
+	recordType, err := dnsRecordType(address)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+
+	record, err := provider.UpdateRecord(ctx, resource.DNSRecord{
+		ID:      recordID,
+		Name:    name,
+		Type:    recordType,
+		Content: address,
+		Comment: comment,
+		TTL:     config.GlobalConfig.GetDNSConfig().TTL,
+	})
+	if err != nil {
+		d.logger.WithError(err).WithFields(logrus.Fields{
+			"provider": provider.Name(),
+			"recordID": recordID,
+		}).Error("failed to update dns record")
+		return resource.DNSRecord{}, err
+	}
+	record.Provider = provider.Name()
+
+	return record, nil
+}
+
+func (d *dnsService) DeleteRecord(ctx context.Context, providerName, recordID string) error {
+	provider, err := d.getProvider(providerName)
+	if err != nil {
+		return err
+	}
+
+	return provider.DeleteRecord(ctx, recordID)
+}
+
+// ListRecords returns the records of the configured DNS_PROVIDER together
+// with its name.
+func (d *dnsService) ListRecords(ctx context.Context) (string, []resource.DNSRecord, error) {
+	provider, err := d.getProvider(config.GlobalConfig.GetDNSConfig().Provider)
+	if err != nil {
+		return "", nil, err
+	}
+
+	records, err := provider.ListRecords(ctx)
+	if err != nil {
+		d.logger.WithError(err).WithField("provider", provider.Name()).Error("failed to list dns records")
+		return "", nil, err
+	}
+	for i := range records {
+		records[i].Provider = provider.Name()
+	}
+
+	return provider.Name(), records, nil
+}
+
+func (d *dnsService) getProvider(name string) (IDNSProvider, error) {
+	if name == "" {
+		name = DNSProviderCloudflare
+	}
+	provider, ok := d.providers[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("dns provider %s is not supported", name)
+	}
+	return provider, nil
+}
+
+func dnsRecordType(address string) (string, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return "", fmt.Errorf("invalid ip address %s", address)
+	}
+	if ip.To4() != nil {
+		return DNSRecordTypeA, nil
+	}
+	return DNSRecordTypeAAAA, nil
+}
+
+// ResolveClusterTLSSANs validates the custom DNS names and the extra TLS SANs
+// of a cluster. It returns the normalized DNS names and every SAN added to the
+// API server certificate next to the generated endpoint. Names must be below
+// one of the DNS_ALLOWED_ZONES, extra SANs may also be IP addresses.
+func ResolveClusterTLSSANs(customDNSNames, extraTLSSANs []string) ([]string, []string, error) {
+	dnsNames := []string{}
+	tlsSANs := []string{}
+	seen := make(map[string]bool)
+
+	for _, name := range customDNSNames {
+		name = strings.ToLower(strings.TrimSuffix(name, "."))
+		err := validateClusterDNSName(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", constants.ErrDNSNameInvalid, err)
+		}
+		if seen[name] {
+			return nil, nil, fmt.Errorf("%s: %s is duplicated", constants.ErrDNSNameInvalid, name)
+		}
+		seen[name] = true
+		dnsNames = append(dnsNames, name)
+		tlsSANs = append(tlsSANs, name)
+	}
+
+	for _, san := range extraTLSSANs {
+		if ip := net.ParseIP(san); ip != nil {
+			san = ip.String()
+		} else {
+			san = strings.ToLower(strings.TrimSuffix(san, "."))
+			err := validateClusterDNSName(san)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %v", constants.ErrDNSNameInvalid, err)
+			}
+		}
+		if seen[san] {
+			continue
+		}
+		seen[san] = true
+		tlsSANs = append(tlsSANs, san)
+	}
+
+	return dnsNames, tlsSANs, nil
+}
+
+func validateClusterDNSName(name string) error {
+	if len(name) == 0 || len(name) > 253 {
+		return fmt.Errorf("%s must be between 1 and 253 characters", name)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if !dnsLabelPattern.MatchString(label) {
+			return fmt.Errorf("%s is not a valid dns name", name)
+		}
+	}
+	for _, zone := range config.GlobalConfig.GetDNSConfig().AllowedZones {
+		zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+		if strings.HasSuffix(name, "."+zone) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not in an allowed zone", name)
+}
+
+// getClusterDNSRecords returns the records of a cluster next to the API
+// endpoint record.
+func getClusterDNSRecords(recordsJSON datatypes.JSON) []resource.DNSRecord {
+	records := []resource.DNSRecord{}
+	if recordsJSON == nil {
+		return records
+	}
+	_ = json.Unmarshal(recordsJSON, &records)
+	return records
+}
+
+func getClusterTLSSANs(sansJSON datatypes.JSON) []string {
+	sans := []string{}
+	if sansJSON == nil {
+		return sans
+	}
+	_ = json.Unmarshal(sansJSON, &sans)
+	return sans
+}
+
+func appsWildcardDNSName(clusterEndpoint string) string {
+	return fmt.Sprintf("*.apps.%s", clusterEndpoint)
+}
+
+// clusterDNSNames returns the names of the records of a cluster next to its
+// endpoint, names with both an A and an AAAA record are listed once.
+func clusterDNSNames(cluster *model.Cluster) []string {
+	names := []string{}
+	seen := map[string]bool{cluster.ClusterEndpoint: true}
+	for _, record := range getClusterDNSRecords(cluster.ClusterDNSRecords) {
+		if seen[record.Name] {
+			continue
+		}
+		seen[record.Name] = true
+		names = append(names, record.Name)
+	}
+	return names
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/repository"
+)
+
+type IDNSGarbageCollectorService interface {
+	Start(ctx context.Context)
+	GetOrphanRecords(ctx context.Context, authToken string) (resource.DNSGarbageCollectionReport, error)
+	DeleteOrphanRecords(ctx context.Context, authToken string) (resource.DNSGarbageCollectionReport, error)
+	CollectGarbage(ctx context.Context, deleteOrphans bool) (resource.DNSGarbageCollectionReport, error)
+}
+
+type dnsGarbageCollectorService struct {
+	logger          *logrus.Logger
+	repository      repository.IRepository
+	identityService IIdentityService
+	dnsService      IDNSService
+}
+
+func NewDNSGarbageCollectorService(l *logrus.Logger, r repository.IRepository, i IIdentityService, dns IDNSService) IDNSGarbageCollectorService {
+	return &dnsGarbageCollectorService{
+		logger:          l,
+		repository:      r,
+		identityService: i,
+		dnsService:      dns,
+	}
+}
+
+// Start runs the DNS garbage collection loop until the context is cancelled.
+// Orphan records are only deleted when DNS_GC_DELETE_ORPHANS is set,
+// otherwise they are logged.
+func (gc *dnsGarbageCollectorService) Start(ctx context.Context) {
+	gcConfig := config.GlobalConfig.GetDNSGarbageCollectionConfig()
+	if !gcConfig.Enabled {
+		gc.logger.Info("dns garbage collector is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(gcConfig.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	gc.logger.WithFields(logrus.Fields{
+		"intervalSeconds": gcConfig.IntervalSeconds,
+		"deleteOrphans":   gcConfig.DeleteOrphans,
+	}).Info("dns garbage collector started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := gc.CollectGarbage(ctx, gcConfig.DeleteOrphans)
+			if err != nil {
+				continue
+			}
+			for _, orphan := range report.Orphans {
+				gc.logger.WithFields(logrus.Fields{
+					"provider": orphan.Provider,
+					"recordID": orphan.ID,
+					"name":     orphan.Name,
+					"comment":  orphan.Comment,
+					"deleted":  orphan.Deleted,
+				}).Warn("found orphan dns record")
+			}
+		}
+	}
+}
+
+func (gc *dnsGarbageCollectorService) GetOrphanRecords(ctx context.Context, authToken string) (resource.DNSGarbageCollectionReport, error) {
+	token := strings.Clone(authToken)
+
+	err := gc.identityService.CheckAdminToken(ctx, token)
+	if err != nil {
+		gc.logger.WithError(err).Error("failed to check admin token")
+		return resource.DNSGarbageCollectionReport{}, err
+	}
+
+	return gc.CollectGarbage(ctx, false)
+}
+
+func (gc *dnsGarbageCollectorService) DeleteOrphanRecords(ctx context.Context, authToken string) (resource.DNSGarbageCollectionReport, error) {
+	token := strings.Clone(authToken)
+
+	err := gc.identityService.CheckAdminToken(ctx, token)
+	if err != nil {
+		gc.logger.WithError(err).Error("failed to check admin token")
+		return resource.DNSGarbageCollectionReport{}, err
+	}
+
+	return gc.CollectGarbage(ctx, true)
+}
+
+// CollectGarbage lists the records of the configured provider and reports the
+// ones created by vke which no live cluster references. vke sets the cluster
+// name as the record comment, so only records commented with the name of a
+// known cluster are considered. Records of clusters which are still being
+// created are skipped, their record ids may not be stored yet.
+func (gc *dnsGarbageCollectorService) CollectGarbage(ctx context.Context, deleteOrphans bool) (resource.DNSGarbageCollectionReport, error) {
+	provider, records, err := gc.dnsService.ListRecords(ctx)
+	if err != nil {
+		return resource.DNSGarbageCollectionReport{}, err
+	}
+
+	clusters, err := gc.repository.Cluster().GetClusters(ctx)
+	if err != nil {
+		gc.logger.WithError(err).Error("failed to get clusters")
+		return resource.DNSGarbageCollectionReport{}, err
+	}
+
+	clusterNames := make(map[string]bool)
+	creatingClusterNames := make(map[string]bool)
+	referencedRecords := make(map[string]bool)
+	for i := range clusters {
+		cluster := &clusters[i]
+		clusterNames[cluster.ClusterName] = true
+		if cluster.ClusterStatus == CreatingClusterStatus {
+			creatingClusterNames[cluster.ClusterName] = true
+		}
+		if cluster.ClusterStatus == DeletedClusterStatus {
+			continue
+		}
+		if cluster.ClusterDNSRecordID != "" {
+			referencedRecords[cluster.ClusterDNSRecordID] = true
+		}
+		for _, record := range getClusterDNSRecords(cluster.ClusterDNSRecords) {
+			referencedRecords[record.ID] = true
+		}
+	}
+
+	report := resource.DNSGarbageCollectionReport{
+		Provider:       provider,
+		ScannedRecords: len(records),
+		Orphans:        []resource.DNSOrphanRecord{},
+		CheckDate:      time.Now(),
+	}
+	for _, record := range records {
+		if record.Comment == "" || !clusterNames[record.Comment] || creatingClusterNames[record.Comment] || referencedRecords[record.ID] {
+			continue
+		}
+
+		orphan := resource.DNSOrphanRecord{DNSRecord: record}
+		if deleteOrphans {
+			err = gc.dnsService.DeleteRecord(ctx, record.Provider, record.ID)
+			if err != nil {
+				gc.logger.WithError(err).WithFields(logrus.Fields{
+					"recordID": record.ID,
+					"name":     record.Name,
+				}).Error("failed to delete orphan dns record")
+				orphan.Error = err.Error()
+			} else {
+				orphan.Deleted = true
+			}
+		}
+		report.Orphans = append(report.Orphans, orphan)
+	}
+
+	return report, nil
+}
@@ -21,6 +21,8 @@ type IIdentityService interface {
 	CheckAuthToken(ctx context.Context, authToken, projectID string) error
 	CreateApplicationCredential(ctx context.Context, clusterUUID, authToken string) (resource.CreateApplicationCredentialResponse, error)
 	DeleteApplicationCredential(ctx context.Context, authToken, projectID string) error
+	GetServiceToken(ctx context.Context, projectUUID string) (string, error)
+	CheckAdminToken(ctx context.Context, authToken string) error
 }
 
 type identityService struct {
@@ -109,6 +111,47 @@ func (i *identityService) GetTokenDetail(ctx context.Context, authToken string)
 	return respDecoder.Token.User.ID, nil
 }
 
+// CheckAdminToken verifies that the token holds the configured
+// OPENSTACK_ADMIN_ROLE, it guards the endpoints which are not project scoped.
+func (i *identityService) CheckAdminToken(ctx context.Context, authToken string) error {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", config.GlobalConfig.GetEndpointsConfig().IdentityEndpoint, constants.TokenPath), nil)
+	if err != nil {
+		i.logger.WithError(err).Error("failed to create request")
+		return err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+	r.Header.Add("X-Subject-Token", token)
+
+	resp, err := i.client.Do(r)
+	if err != nil {
+		i.logger.WithError(err).Error("failed to send request")
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to check auth token, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.GetTokenDetailsResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		i.logger.WithError(err).Error("failed to decode response")
+		return err
+	}
+
+	adminRole := config.GlobalConfig.GetOpenstackRolesConfig().OpenstackAdminRole
+	for _, role := range respDecoder.Token.Roles {
+		if role.Name == adminRole {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to check auth token, %s role is required", adminRole)
+}
+
 func (i *identityService) CreateApplicationCredential(ctx context.Context, clusterUUID, authToken string) (resource.CreateApplicationCredentialResponse, error) {
 	token := strings.Clone(authToken)
 	GenerateSecret := uuid.New().String()
@@ -196,3 +239,64 @@ func (i *identityService) DeleteApplicationCredential(ctx context.Context, authT
 	}
 	return nil
 }
+
+// GetServiceToken issues a project scoped token with the configured service
+// account. It is used by background jobs which run without a user token.
+func (i *identityService) GetServiceToken(ctx context.Context, projectUUID string) (string, error) {
+	serviceAccount := config.GlobalConfig.GetServiceAccountConfig()
+	if serviceAccount.Username == "" || serviceAccount.Password == "" {
+		return "", fmt.Errorf("service account is not configured")
+	}
+
+	createTokenReq := &request.CreateTokenRequest{
+		Auth: request.TokenAuth{
+			Identity: request.TokenIdentity{
+				Methods: []string{"password"},
+				Password: request.TokenPassword{
+					User: request.TokenUser{
+						Name:     serviceAccount.Username,
+						Password: serviceAccount.Password,
+						Domain: request.TokenDomain{
+							Name: serviceAccount.DomainName,
+						},
+					},
+				},
+			},
+			Scope: request.TokenScope{
+				Project: request.TokenProject{
+					ID: projectUUID,
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(createTokenReq)
+	if err != nil {
+		i.logger.WithError(err).Error("failed to marshal request")
+		return "", err
+	}
+
+	r, err := http.NewRequest("POST", fmt.Sprintf("%s/%s", config.GlobalConfig.GetEndpointsConfig().IdentityEndpoint, constants.TokenPath), bytes.NewBuffer(data))
+	if err != nil {
+		i.logger.WithError(err).Error("failed to create request")
+		return "", err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("Content-Type", "application/json")
+
+	resp, err := i.client.Do(r)
+	if err != nil {
+		i.logger.WithError(err).Error("failed to send request")
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("failed to create service token, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	token := resp.Header.Get("X-Subject-Token")
+	if token == "" {
+		return "", fmt.Errorf("failed to create service token, token is empty")
+	}
+
+	return token, nil
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/pkg/constants"
+)
+
+const (
+	ImageStatusActive     = "active"
+	ImageVisibilityPublic = "public"
+	ImageVisibilityShared = "shared"
+)
+
+type IImageService interface {
+	GetImage(ctx context.Context, authToken, imageID string) (resource.GlanceImage, error)
+	ValidateImage(ctx context.Context, authToken, projectUUID, imageRef string) (string, error)
+	GetImageCatalog(ctx context.Context, authToken, projectID string) ([]resource.Image, error)
+}
+
+type imageService struct {
+	logger          *logrus.Logger
+	identityService IIdentityService
+	client          http.Client
+}
+
+func NewImageService(l *logrus.Logger, i IIdentityService) IImageService {
+	return &imageService{
+		logger:          l,
+		identityService: i,
+		client:          CreateHTTPClient(),
+	}
+}
+
+func (is *imageService) GetImage(ctx context.Context, authToken, imageID string) (resource.GlanceImage, error) {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().ImageEndpoint, constants.ImagePath, imageID), nil)
+	if err != nil {
+		is.logger.WithError(err).Error("failed to create request")
+		return resource.GlanceImage{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+	r.Header.Add("Content-Type", "application/json")
+
+	resp, err := is.client.Do(r)
+	if err != nil {
+		is.logger.WithError(err).Error("failed to send request")
+		return resource.GlanceImage{}, err
+	}
+	defer resp.Body.Close()
+
+	// Glance answers with 404 for images that are not visible to the project.
+	if resp.StatusCode == http.StatusNotFound {
+		return resource.GlanceImage{}, fmt.Errorf("%s: %s", constants.ErrImageNotFound, imageID)
+	}
+	if resp.StatusCode != http.StatusOK {
+		is.logger.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"error_msg":   resp.Status,
+		}).Error("failed to get image")
+		return resource.GlanceImage{}, fmt.Errorf("failed to get image, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		is.logger.WithError(err).Error("failed to read response body")
+		return resource.GlanceImage{}, err
+	}
+
+	var respData resource.GlanceImage
+	err = json.Unmarshal(body, &respData)
+	if err != nil {
+		is.logger.WithError(err).Error("failed to unmarshal response body")
+		return resource.GlanceImage{}, err
+	}
+
+	return respData, nil
+}
+
+// ValidateImage checks that the image exists, is active and is visible to the
+// project. An empty image reference resolves to the global IMAGE_REF. When an
+// image catalog is configured only the images listed in it are accepted.
+func (is *imageService) ValidateImage(ctx context.Context, authToken, projectUUID, imageRef string) (string, error) {
+	if imageRef == "" {
+		return config.GlobalConfig.GetImageRefConfig().ImageRef, nil
+	}
+
+	catalog := config.GlobalConfig.GetImageCatalogConfig().Images
+	if len(catalog) > 0 && imageRef != config.GlobalConfig.GetImageRefConfig().ImageRef {
+		approved := false
+		for _, item := range catalog {
+			if item.ImageRef == imageRef {
+				approved = true
+				break
+			}
+		}
+		if !approved {
+			return "", fmt.Errorf("%s: %s", constants.ErrImageNotApproved, imageRef)
+		}
+	}
+
+	image, err := is.GetImage(ctx, authToken, imageRef)
+	if err != nil {
+		return "", err
+	}
+
+	err = checkImageUsable(image, projectUUID)
+	if err != nil {
+		return "", err
+	}
+
+	return imageRef, nil
+}
+
+func checkImageUsable(image resource.GlanceImage, projectUUID string) error {
+	if image.Status != ImageStatusActive {
+		return fmt.Errorf("%s: %s, status: %s", constants.ErrImageNotActive, image.ID, image.Status)
+	}
+
+	// Admin tokens can read private images of other projects, so the owner is
+	// checked as well.
+	if image.Visibility != ImageVisibilityPublic && image.Visibility != ImageVisibilityShared && image.Owner != projectUUID {
+		return fmt.Errorf("%s: %s", constants.ErrImageNotFound, image.ID)
+	}
+
+	return nil
+}
+
+func (is *imageService) GetImageCatalog(ctx context.Context, authToken, projectID string) ([]resource.Image, error) {
+	token := strings.Clone(authToken)
+	err := is.identityService.CheckAuthToken(ctx, token, projectID)
+	if err != nil {
+		is.logger.WithError(err).WithFields(logrus.Fields{
+			"projectID": projectID,
+		}).Error("failed to check auth token")
+		return nil, err
+	}
+
+	defaultImageRef := config.GlobalConfig.GetImageRefConfig().ImageRef
+	catalog := config.GlobalConfig.GetImageCatalogConfig().Images
+	if len(catalog) == 0 {
+		catalog = []config.ImageCatalogItem{{ImageRef: defaultImageRef}}
+	}
+
+	images := []resource.Image{}
+	for _, item := range catalog {
+		image, err := is.GetImage(ctx, token, item.ImageRef)
+		if err == nil {
+			err = checkImageUsable(image, projectID)
+		}
+		if err != nil {
+			is.logger.WithError(err).WithFields(logrus.Fields{
+				"imageRef": item.ImageRef,
+			}).Warn("image in catalog is not available for the project")
+			continue
+		}
+
+		osName := item.OSName
+		if osName == "" {
+			osName = image.OSDistro
+		}
+		osVersion := item.OSVersion
+		if osVersion == "" {
+			osVersion = image.OSVersion
+		}
+
+		images = append(images, resource.Image{
+			ImageRef:  item.ImageRef,
+			Name:      image.Name,
+			OSName:    osName,
+			OSVersion: osVersion,
+			Default:   item.ImageRef == defaultImageRef,
+		})
+	}
+
+	return images, nil
+}
@@ -0,0 +1,771 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/internal/repository"
+)
+
+const (
+	IngressProtocolHTTP  = "HTTP"
+	IngressProtocolHTTPS = "HTTPS"
+	IngressProtocolTCP   = "TCP"
+
+	ingressNodePortMin = 30000
+	ingressNodePortMax = 32767
+	maxIngressListener = 10
+)
+
+type IIngressService interface {
+	CreateIngressLoadBalancer(ctx context.Context, authToken, clusterID string, req request.CreateIngressLoadBalancerRequest) (resource.IngressLoadBalancer, error)
+	GetIngressLoadBalancer(ctx context.Context, authToken, clusterID string) (resource.IngressLoadBalancer, error)
+	DeleteIngressLoadBalancer(ctx context.Context, authToken, clusterID string) error
+	DeleteClusterIngressLoadBalancer(ctx context.Context, authToken, clusterUUID string) error
+	AddNodeMember(ctx context.Context, authToken, clusterUUID, nodeGroupUUID, serverName, subnetID, address string) error
+	RemoveNodeMembers(ctx context.Context, authToken, clusterUUID, nodeGroupUUID, serverID string) error
+}
+
+type ingressService struct {
+	logger              *logrus.Logger
+	repository          repository.IRepository
+	identityService     IIdentityService
+	loadbalancerService ILoadbalancerService
+	networkService      INetworkService
+	computeService      IComputeService
+	dnsService          IDNSService
+}
+
+func NewIngressService(l *logrus.Logger, r repository.IRepository, i IIdentityService, lbc ILoadbalancerService, ns INetworkService, cs IComputeService, dns IDNSService) IIngressService {
+	return &ingressService{
+		logger:              l,
+		repository:          r,
+		identityService:     i,
+		loadbalancerService: lbc,
+		networkService:      ns,
+		computeService:      cs,
+		dnsService:          dns,
+	}
+}
+
+func (is *ingressService) getCluster(ctx context.Context, token, clusterID string) (*model.Cluster, error) {
+	cluster, err := is.repository.Cluster().GetClusterByUUID(ctx, clusterID)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterID": clusterID,
+		}).WithError(err).Error("failed to get cluster by uuid")
+		return nil, err
+	}
+	if cluster == nil || cluster.ClusterProjectUUID == "" {
+		return nil, fmt.Errorf("failed to get cluster")
+	}
+
+	err = is.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterProjectUUID": cluster.ClusterProjectUUID,
+		}).WithError(err).Error("failed to check auth token")
+		return nil, err
+	}
+	return cluster, nil
+}
+
+func validateIngressListeners(listeners []request.IngressListener, provider string) error {
+	if len(listeners) == 0 {
+		return fmt.Errorf("at least one listener is required")
+	}
+	if len(listeners) > maxIngressListener {
+		return fmt.Errorf("ingress load balancer supports at most %d listeners", maxIngressListener)
+	}
+
+	ports := map[int]bool{}
+	for _, listener := range listeners {
+		switch listener.Protocol {
+		case IngressProtocolHTTP, IngressProtocolHTTPS, IngressProtocolTCP:
+		default:
+			return fmt.Errorf("listener protocol must be one of %s, %s, %s", IngressProtocolHTTP, IngressProtocolHTTPS, IngressProtocolTCP)
+		}
+		if provider == LoadBalancerProviderOVN && listener.Protocol != IngressProtocolTCP {
+			return fmt.Errorf("provider %s only supports %s listeners", LoadBalancerProviderOVN, IngressProtocolTCP)
+		}
+		if listener.Port < 1 || listener.Port > 65535 {
+			return fmt.Errorf("invalid listener port %d", listener.Port)
+		}
+		if ports[listener.Port] {
+			return fmt.Errorf("listener port %d is duplicated", listener.Port)
+		}
+		ports[listener.Port] = true
+		if listener.NodePort < ingressNodePortMin || listener.NodePort > ingressNodePortMax {
+			return fmt.Errorf("node port must be between %d and %d", ingressNodePortMin, ingressNodePortMax)
+		}
+	}
+	return nil
+}
+
+func (is *ingressService) CreateIngressLoadBalancer(ctx context.Context, authToken, clusterID string, req request.CreateIngressLoadBalancerRequest) (resource.IngressLoadBalancer, error) {
+	token := strings.Clone(authToken)
+
+	cluster, err := is.getCluster(ctx, token, clusterID)
+	if err != nil {
+		return resource.IngressLoadBalancer{}, err
+	}
+	if cluster.ClusterStatus != ActiveClusterStatus {
+		return resource.IngressLoadBalancer{}, fmt.Errorf("cluster is not active")
+	}
+
+	existing, err := is.repository.IngressLoadBalancers().GetIngressLoadBalancerByClusterUUID(ctx, cluster.ClusterUUID)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to get ingress load balancer")
+		return resource.IngressLoadBalancer{}, err
+	}
+	if existing != nil {
+		return resource.IngressLoadBalancer{}, fmt.Errorf("cluster already has an ingress load balancer")
+	}
+
+	nodeGroup, err := is.repository.NodeGroups().GetNodeGroupByUUID(ctx, req.NodeGroupID)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"nodeGroupID": req.NodeGroupID,
+		}).WithError(err).Error("failed to get node group by uuid")
+		return resource.IngressLoadBalancer{}, err
+	}
+	if nodeGroup == nil || nodeGroup.ClusterUUID != cluster.ClusterUUID || nodeGroup.NodeGroupsStatus == NodeGroupDeletedStatus {
+		return resource.IngressLoadBalancer{}, fmt.Errorf("failed to get node group")
+	}
+	if nodeGroup.NodeGroupsType != NodeGroupWorkerType {
+		return resource.IngressLoadBalancer{}, fmt.Errorf("ingress load balancer must target a worker node group")
+	}
+
+	err = validateIngressListeners(req.Listeners, clusterLoadBalancerProvider(cluster))
+	if err != nil {
+		return resource.IngressLoadBalancer{}, err
+	}
+
+	subnetIDs := []string{}
+	err = json.Unmarshal(cluster.ClusterSubnets, &subnetIDs)
+	if err != nil || len(subnetIDs) == 0 {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to unmarshal cluster subnets")
+		return resource.IngressLoadBalancer{}, fmt.Errorf("failed to get subnet ids")
+	}
+	clusterSubnets, err := is.networkService.GetClusterSubnets(ctx, token, subnetIDs)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to get cluster subnets")
+		return resource.IngressLoadBalancer{}, err
+	}
+
+	lbResp, err := is.loadbalancerService.CreateLoadBalancer(ctx, token, request.CreateLoadBalancerRequest{
+		LoadBalancer: request.LoadBalancer{
+			Name:           fmt.Sprintf("%v-ingress-lb", cluster.ClusterName),
+			Description:    fmt.Sprintf("%v-ingress-lb", cluster.ClusterName),
+			AdminStateUp:   true,
+			VIPSubnetID:    clusterSubnets.VIPSubnetID(),
+			Provider:       clusterLoadBalancerProvider(cluster),
+			FlavorID:       cluster.ClusterLBFlavorID,
+			AdditionalVIPs: clusterSubnets.AdditionalVIPs(),
+		},
+	})
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to create ingress load balancer")
+		return resource.IngressLoadBalancer{}, err
+	}
+
+	listeners := []resource.IngressListener{}
+	for _, listener := range req.Listeners {
+		listeners = append(listeners, resource.IngressListener{
+			Protocol: listener.Protocol,
+			Port:     listener.Port,
+			NodePort: listener.NodePort,
+		})
+	}
+	listenersJSON, err := json.Marshal(listeners)
+	if err != nil {
+		return resource.IngressLoadBalancer{}, err
+	}
+
+	ingressLoadBalancer := &model.IngressLoadBalancer{
+		ClusterUUID:      cluster.ClusterUUID,
+		NodeGroupUUID:    nodeGroup.NodeGroupUUID,
+		LoadBalancerUUID: lbResp.LoadBalancer.ID,
+		Listeners:        listenersJSON,
+		CreateDate:       time.Now(),
+	}
+	err = is.repository.IngressLoadBalancers().CreateIngressLoadBalancer(ctx, ingressLoadBalancer)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to create ingress load balancer record")
+		return resource.IngressLoadBalancer{}, err
+	}
+
+	is.createAuditLog(ctx, cluster, "Ingress load balancer create started")
+
+	go is.provisionIngressLoadBalancer(context.Background(), token, cluster, nodeGroup, subnetIDs, ingressLoadBalancer, listeners, req.Public)
+
+	return ingressLoadBalancerResponse(ingressLoadBalancer, listeners, resource.ListLoadBalancer{ProvisioningStatus: LoadBalancerStatusPendingCreate}), nil
+}
+
+// ingressPoolAlgorithm returns the pool algorithm of the ingress listeners,
+// the OVN provider only supports SOURCE_IP_PORT.
+func ingressPoolAlgorithm(provider string) string {
+	if provider == LoadBalancerProviderOVN {
+		return LBAlgorithmSourceIPPort
+	}
+	return LBAlgorithmRoundRobin
+}
+
+// provisionIngressLoadBalancer creates the listeners, pools, health monitors
+// and members once the load balancer is active. Octavia rejects changes while
+// the load balancer is pending, so every step waits for it to become active.
+// The record is saved once the floating IP exists and again when a step
+// fails, so deleting the ingress load balancer releases what was created.
+func (is *ingressService) provisionIngressLoadBalancer(ctx context.Context, token string, cluster *model.Cluster, nodeGroup *model.NodeGroups, subnetIDs []string, ingressLoadBalancer *model.IngressLoadBalancer, listeners []resource.IngressListener, public bool) {
+	fail := func(err error, msg string) {
+		is.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID":      cluster.ClusterUUID,
+			"loadBalancerUUID": ingressLoadBalancer.LoadBalancerUUID,
+		}).Error(msg)
+		is.createAuditLog(ctx, cluster, fmt.Sprintf("Ingress load balancer create failed: %s", msg))
+		if err := is.saveIngressLoadBalancer(ctx, ingressLoadBalancer, listeners); err != nil {
+			is.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to update ingress load balancer record")
+		}
+	}
+	lbID := ingressLoadBalancer.LoadBalancerUUID
+
+	_, err := is.loadbalancerService.CheckLoadBalancerStatus(ctx, token, lbID)
+	if err != nil {
+		fail(err, "failed to check load balancer status")
+		return
+	}
+	lb, err := is.loadbalancerService.ListLoadBalancer(ctx, token, lbID)
+	if err != nil {
+		fail(err, "failed to list load balancer")
+		return
+	}
+	ingressLoadBalancer.VIPAddress = lb.LoadBalancer.VIPAddress
+
+	// floating IPs are IPv4 only, IPv6 VIPs are reached directly
+	if vip := net.ParseIP(lb.LoadBalancer.VIPAddress); public && vip != nil && vip.To4() != nil {
+		floatingIPResp, err := is.networkService.CreateFloatingIP(ctx, token, request.CreateFloatingIPRequest{
+			FloatingIP: request.FloatingIP{
+				FloatingNetworkID: config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+				PortID:            lb.LoadBalancer.VipPortID,
+			},
+		})
+		if err != nil {
+			fail(err, "failed to create floating ip")
+			return
+		}
+		ingressLoadBalancer.FloatingIPUUID = floatingIPResp.FloatingIP.ID
+		ingressLoadBalancer.FloatingIP = floatingIPResp.FloatingIP.FloatingIP
+		err = is.saveIngressLoadBalancer(ctx, ingressLoadBalancer, listeners)
+		if err != nil {
+			fail(err, "failed to update ingress load balancer record")
+			return
+		}
+	}
+
+	subnetCIDRs := getSubnetCIDRs(ctx, is.logger, is.networkService, token, subnetIDs)
+	err = is.allowNodePorts(ctx, token, nodeGroup.NodeGroupSecurityGroup, subnetCIDRs, listeners)
+	if err != nil {
+		fail(err, "failed to create node port security group rules")
+		return
+	}
+
+	servers, err := is.computeService.GetInstances(ctx, token, nodeGroup.NodeGroupUUID)
+	if err != nil {
+		fail(err, "failed to get instances")
+		return
+	}
+
+	for i := range listeners {
+		listener := &listeners[i]
+		name := fmt.Sprintf("%v-ingress-%s-%d", cluster.ClusterName, strings.ToLower(listener.Protocol), listener.Port)
+
+		listenerResp, err := is.loadbalancerService.CreateListener(ctx, token, request.CreateListenerRequest{
+			Listener: request.Listener{
+				Name:           name,
+				AdminStateUp:   true,
+				Protocol:       listener.Protocol,
+				ProtocolPort:   listener.Port,
+				LoadbalancerID: lbID,
+			},
+		})
+		if err != nil {
+			fail(err, "failed to create listener")
+			return
+		}
+		listener.ListenerID = listenerResp.Listener.ID
+		_, err = is.loadbalancerService.CheckLoadBalancerStatus(ctx, token, lbID)
+		if err != nil {
+			fail(err, "failed to check load balancer status")
+			return
+		}
+
+		poolResp, err := is.loadbalancerService.CreatePool(ctx, token, request.CreatePoolRequest{
+			Pool: request.Pool{
+				LBAlgorithm:  ingressPoolAlgorithm(clusterLoadBalancerProvider(cluster)),
+				Protocol:     listener.Protocol,
+				AdminStateUp: true,
+				ListenerID:   listener.ListenerID,
+				Name:         fmt.Sprintf("%s-pool", name),
+			},
+		})
+		if err != nil {
+			fail(err, "failed to create pool")
+			return
+		}
+		listener.PoolID = poolResp.Pool.ID
+		_, err = is.loadbalancerService.CheckLoadBalancerStatus(ctx, token, lbID)
+		if err != nil {
+			fail(err, "failed to check load balancer status")
+			return
+		}
+
+		err = is.loadbalancerService.CreateHealthTCPMonitor(ctx, token, request.CreateHealthMonitorTCPRequest{
+			HealthMonitor: request.HealthMonitorTCP{
+				Name:           fmt.Sprintf("%s-healthmonitor", name),
+				AdminStateUp:   true,
+				PoolID:         listener.PoolID,
+				MaxRetries:     "3",
+				Delay:          "10",
+				TimeOut:        "5",
+				Type:           "TCP",
+				MaxRetriesDown: 3,
+			},
+		})
+		if err != nil {
+			fail(err, "failed to create health monitor")
+			return
+		}
+		_, err = is.loadbalancerService.CheckLoadBalancerStatus(ctx, token, lbID)
+		if err != nil {
+			fail(err, "failed to check load balancer status")
+			return
+		}
+
+		for _, server := range servers {
+			address := clusterSubnetAddress(subnetCIDRs, server.FixedIPs)
+			if address == "" {
+				continue
+			}
+			err = is.createMember(ctx, token, lbID, listener.PoolID, server.Name, memberSubnetID(subnetCIDRs, subnetIDs, address), address, listener.NodePort)
+			if err != nil {
+				fail(err, "failed to create member")
+				return
+			}
+		}
+	}
+
+	err = is.saveIngressLoadBalancer(ctx, ingressLoadBalancer, listeners)
+	if err != nil {
+		fail(err, "failed to update ingress load balancer record")
+		return
+	}
+
+	is.createAuditLog(ctx, cluster, "Ingress load balancer created")
+
+	if cluster.ClusterAppsDNSTarget == AppsDNSTargetIngress {
+		address := ingressLoadBalancer.FloatingIP
+		if address == "" {
+			address = ingressLoadBalancer.VIPAddress
+		}
+		err = is.saveAppsDNSRecord(ctx, cluster, address)
+		if err != nil {
+			is.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to save apps dns record")
+			is.createAuditLog(ctx, cluster, "Apps DNS record create failed")
+		}
+	}
+}
+
+// saveIngressLoadBalancer stores the listeners and the addresses of the
+// ingress load balancer.
+func (is *ingressService) saveIngressLoadBalancer(ctx context.Context, ingressLoadBalancer *model.IngressLoadBalancer, listeners []resource.IngressListener) error {
+	listenersJSON, err := json.Marshal(listeners)
+	if err != nil {
+		return err
+	}
+	ingressLoadBalancer.Listeners = listenersJSON
+	return is.repository.IngressLoadBalancers().UpdateIngressLoadBalancer(ctx, ingressLoadBalancer)
+}
+
+// saveAppsDNSRecord points the apps wildcard record of the cluster at the
+// address, creating it when the cluster has none yet. It runs minutes after
+// the cluster was loaded, so the cluster is read again and nothing is created
+// once the cluster is no longer active, e.g. because it is being destroyed.
+func (is *ingressService) saveAppsDNSRecord(ctx context.Context, cluster *model.Cluster, address string) error {
+	clusterUUID := cluster.ClusterUUID
+	cluster, err := is.repository.Cluster().GetClusterByUUID(ctx, clusterUUID)
+	if err != nil {
+		return err
+	}
+	if cluster == nil || cluster.ClusterStatus != ActiveClusterStatus {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Warn("skipping apps dns record, cluster is no longer active")
+		return nil
+	}
+
+	name := appsWildcardDNSName(cluster.ClusterEndpoint)
+	records := getClusterDNSRecords(cluster.ClusterDNSRecords)
+
+	found := false
+	for i := range records {
+		if records[i].Name != name {
+			continue
+		}
+		found = true
+		records[i], err = is.dnsService.UpdateRecord(ctx, records[i].Provider, records[i].ID, name, address, cluster.ClusterName)
+		if err != nil {
+			return err
+		}
+	}
+	if !found {
+		record, err := is.dnsService.CreateRecord(ctx, name, address, cluster.ClusterName)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+
+	return is.saveClusterDNSRecords(ctx, cluster, records)
+}
+
+// deleteAppsDNSRecord removes the apps wildcard record when it points at the
+// ingress load balancer. On cluster deletion the record is removed with the
+// other DNS records of the cluster instead.
+func (is *ingressService) deleteAppsDNSRecord(ctx context.Context, cluster *model.Cluster) error {
+	if cluster.ClusterAppsDNSTarget != AppsDNSTargetIngress {
+		return nil
+	}
+
+	name := appsWildcardDNSName(cluster.ClusterEndpoint)
+	records := []resource.DNSRecord{}
+	for _, record := range getClusterDNSRecords(cluster.ClusterDNSRecords) {
+		if record.Name != name {
+			records = append(records, record)
+			continue
+		}
+		err := is.dnsService.DeleteRecord(ctx, record.Provider, record.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return is.saveClusterDNSRecords(ctx, cluster, records)
+}
+
+func (is *ingressService) saveClusterDNSRecords(ctx context.Context, cluster *model.Cluster, records []resource.DNSRecord) error {
+	recordsJSON, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	cluster.ClusterDNSRecords = recordsJSON
+	return is.repository.Cluster().UpdateClusterDNSRecords(ctx, cluster.ClusterUUID, recordsJSON)
+}
+
+func (is *ingressService) createMember(ctx context.Context, token, lbID, poolID, name, subnetID, address string, port int) error {
+	err := is.loadbalancerService.CreateMember(ctx, token, poolID, request.AddMemberRequest{
+		Member: request.Member{
+			Name:         name,
+			AdminStateUp: true,
+			SubnetID:     subnetID,
+			Address:      address,
+			ProtocolPort: port,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = is.loadbalancerService.CheckLoadBalancerStatus(ctx, token, lbID)
+	return err
+}
+
+// allowNodePorts opens the node ports of the listeners on the node group
+// security group for the cluster subnets, where the amphorae reach the nodes.
+func (is *ingressService) allowNodePorts(ctx context.Context, token, securityGroupID string, subnetCIDRs map[string]string, listeners []resource.IngressListener) error {
+	for _, listener := range listeners {
+		for _, cidr := range subnetCIDRs {
+			err := is.networkService.CreateSecurityGroupRuleForIP(ctx, token, request.CreateSecurityGroupRuleForIpRequest{
+				SecurityGroupRule: request.SecurityGroupRuleForIP{
+					Direction:       "ingress",
+					PortRangeMin:    strconv.Itoa(listener.NodePort),
+					PortRangeMax:    strconv.Itoa(listener.NodePort),
+					Ethertype:       SecurityGroupEthertype(cidr),
+					Protocol:        "tcp",
+					SecurityGroupID: securityGroupID,
+					RemoteIPPrefix:  cidr,
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getSubnetCIDRs returns the CIDR of every cluster subnet keyed by subnet id,
+// subnets that can not be read are skipped.
+func getSubnetCIDRs(ctx context.Context, logger *logrus.Logger, ns INetworkService, token string, subnetIDs []string) map[string]string {
+	subnetCIDRs := map[string]string{}
+	for _, subnetID := range subnetIDs {
+		subnet, err := ns.GetSubnetByID(ctx, token, subnetID)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"subnetID": subnetID,
+			}).WithError(err).Warn("failed to get subnet")
+			continue
+		}
+		subnetCIDRs[subnetID] = subnet.Subnet.CIDR
+	}
+	return subnetCIDRs
+}
+
+// memberSubnetID returns the cluster subnet containing the address, falling
+// back to the first subnet of the cluster.
+func memberSubnetID(subnetCIDRs map[string]string, subnetIDs []string, address string) string {
+	ip := net.ParseIP(address)
+	for subnetID, cidr := range subnetCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err == nil && ip != nil && ipNet.Contains(ip) {
+			return subnetID
+		}
+	}
+	return subnetIDs[0]
+}
+
+func (is *ingressService) GetIngressLoadBalancer(ctx context.Context, authToken, clusterID string) (resource.IngressLoadBalancer, error) {
+	token := strings.Clone(authToken)
+
+	cluster, err := is.getCluster(ctx, token, clusterID)
+	if err != nil {
+		return resource.IngressLoadBalancer{}, err
+	}
+
+	ingressLoadBalancer, err := is.repository.IngressLoadBalancers().GetIngressLoadBalancerByClusterUUID(ctx, cluster.ClusterUUID)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to get ingress load balancer")
+		return resource.IngressLoadBalancer{}, err
+	}
+	if ingressLoadBalancer == nil {
+		return resource.IngressLoadBalancer{}, fmt.Errorf("cluster has no ingress load balancer")
+	}
+
+	listeners := []resource.IngressListener{}
+	if ingressLoadBalancer.Listeners != nil {
+		err = json.Unmarshal(ingressLoadBalancer.Listeners, &listeners)
+		if err != nil {
+			return resource.IngressLoadBalancer{}, err
+		}
+	}
+
+	lb, err := is.loadbalancerService.ListLoadBalancer(ctx, token, ingressLoadBalancer.LoadBalancerUUID)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"loadBalancerUUID": ingressLoadBalancer.LoadBalancerUUID,
+		}).WithError(err).Warn("failed to list ingress load balancer")
+	}
+
+	return ingressLoadBalancerResponse(ingressLoadBalancer, listeners, lb.LoadBalancer), nil
+}
+
+func ingressLoadBalancerResponse(ingressLoadBalancer *model.IngressLoadBalancer, listeners []resource.IngressListener, lb resource.ListLoadBalancer) resource.IngressLoadBalancer {
+	return resource.IngressLoadBalancer{
+		ClusterUUID:        ingressLoadBalancer.ClusterUUID,
+		NodeGroupUUID:      ingressLoadBalancer.NodeGroupUUID,
+		LoadBalancerUUID:   ingressLoadBalancer.LoadBalancerUUID,
+		VIPAddress:         ingressLoadBalancer.VIPAddress,
+		FloatingIP:         ingressLoadBalancer.FloatingIP,
+		ProvisioningStatus: lb.ProvisioningStatus,
+		OperatingStatus:    lb.OperatingStatus,
+		Listeners:          listeners,
+		CreateDate:         ingressLoadBalancer.CreateDate,
+	}
+}
+
+func (is *ingressService) DeleteIngressLoadBalancer(ctx context.Context, authToken, clusterID string) error {
+	token := strings.Clone(authToken)
+
+	cluster, err := is.getCluster(ctx, token, clusterID)
+	if err != nil {
+		return err
+	}
+
+	err = is.DeleteClusterIngressLoadBalancer(ctx, token, cluster.ClusterUUID)
+	if err != nil {
+		return err
+	}
+
+	err = is.deleteAppsDNSRecord(ctx, cluster)
+	if err != nil {
+		is.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to delete apps dns record")
+		return err
+	}
+
+	is.createAuditLog(ctx, cluster, "Ingress load balancer deleted")
+	return nil
+}
+
+// DeleteClusterIngressLoadBalancer removes the ingress load balancer of the
+// cluster together with its floating ip, it is a no-op when there is none.
+func (is *ingressService) DeleteClusterIngressLoadBalancer(ctx context.Context, authToken, clusterUUID string) error {
+	token := strings.Clone(authToken)
+
+	ingressLoadBalancer, err := is.repository.IngressLoadBalancers().GetIngressLoadBalancerByClusterUUID(ctx, clusterUUID)
+	if err != nil {
+		is.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).WithError(err).Error("failed to get ingress load balancer")
+		return err
+	}
+	if ingressLoadBalancer == nil {
+		return nil
+	}
+
+	if ingressLoadBalancer.FloatingIPUUID != "" {
+		err = is.networkService.DeleteFloatingIP(ctx, token, ingressLoadBalancer.FloatingIPUUID)
+		if err != nil {
+			is.logger.WithFields(logrus.Fields{
+				"floatingIPUUID": ingressLoadBalancer.FloatingIPUUID,
+			}).WithError(err).Error("failed to delete ingress floating ip")
+			return err
+		}
+	}
+
+	err = deleteLoadBalancerWithComponents(ctx, is.logger, is.loadbalancerService, token, clusterUUID, ingressLoadBalancer.LoadBalancerUUID)
+	if err != nil {
+		return err
+	}
+
+	return is.repository.IngressLoadBalancers().DeleteIngressLoadBalancer(ctx, clusterUUID)
+}
+
+// ingressPools returns the pools of the ingress load balancer when it targets
+// the node group.
+func (is *ingressService) ingressPools(ctx context.Context, clusterUUID, nodeGroupUUID string) (*model.IngressLoadBalancer, []resource.IngressListener, error) {
+	ingressLoadBalancer, err := is.repository.IngressLoadBalancers().GetIngressLoadBalancerByClusterUUID(ctx, clusterUUID)
+	if err != nil || ingressLoadBalancer == nil || ingressLoadBalancer.NodeGroupUUID != nodeGroupUUID {
+		return nil, nil, err
+	}
+
+	listeners := []resource.IngressListener{}
+	if ingressLoadBalancer.Listeners != nil {
+		err = json.Unmarshal(ingressLoadBalancer.Listeners, &listeners)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return ingressLoadBalancer, listeners, nil
+}
+
+// AddNodeMember adds a new node of the node group to every ingress pool.
+func (is *ingressService) AddNodeMember(ctx context.Context, authToken, clusterUUID, nodeGroupUUID, serverName, subnetID, address string) error {
+	token := strings.Clone(authToken)
+
+	ingressLoadBalancer, listeners, err := is.ingressPools(ctx, clusterUUID, nodeGroupUUID)
+	if err != nil || ingressLoadBalancer == nil {
+		return err
+	}
+
+	for _, listener := range listeners {
+		if listener.PoolID == "" {
+			continue
+		}
+		err = is.createMember(ctx, token, ingressLoadBalancer.LoadBalancerUUID, listener.PoolID, serverName, subnetID, address, listener.NodePort)
+		if err != nil {
+			is.logger.WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+				"poolID":      listener.PoolID,
+				"address":     address,
+			}).WithError(err).Error("failed to add ingress pool member")
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveNodeMembers removes a node of the node group from every ingress pool,
+// it must be called before the server is deleted.
+func (is *ingressService) RemoveNodeMembers(ctx context.Context, authToken, clusterUUID, nodeGroupUUID, serverID string) error {
+	token := strings.Clone(authToken)
+
+	ingressLoadBalancer, listeners, err := is.ingressPools(ctx, clusterUUID, nodeGroupUUID)
+	if err != nil || ingressLoadBalancer == nil {
+		return err
+	}
+
+	server, err := is.computeService.GetInstancesDetail(ctx, token, serverID)
+	if err != nil {
+		return err
+	}
+	addresses := map[string]bool{}
+	for _, networkAddresses := range server.OpenstackServers.Addresses {
+		for _, address := range networkAddresses {
+			addresses[address.Addr] = true
+		}
+	}
+
+	for _, listener := range listeners {
+		if listener.PoolID == "" {
+			continue
+		}
+		members, err := is.loadbalancerService.ListMembers(ctx, token, listener.PoolID)
+		if err != nil {
+			return err
+		}
+		for _, member := range members.Members {
+			if !addresses[member.Address] && member.Name != server.OpenstackServers.Name {
+				continue
+			}
+			err = is.loadbalancerService.DeleteMember(ctx, token, listener.PoolID, member.ID)
+			if err != nil {
+				return err
+			}
+			_, err = is.loadbalancerService.CheckLoadBalancerStatus(ctx, token, ingressLoadBalancer.LoadBalancerUUID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (is *ingressService) createAuditLog(ctx context.Context, cluster *model.Cluster, event string) {
+	err := is.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
+		ClusterUUID: cluster.ClusterUUID,
+		ProjectUUID: cluster.ClusterProjectUUID,
+		Event:       event,
+		CreateDate:  time.Now(),
+	})
+	if err != nil {
+		is.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to create audit log")
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/repository"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	KubernetesNodesPath = "api/v1/nodes"
+	KubernetesPodsPath  = "api/v1/pods"
+
+	KubernetesDaemonSetKind       = "DaemonSet"
+	KubernetesMirrorPodAnnotation = "kubernetes.io/config.mirror"
+	KubernetesPodSucceededPhase   = "Succeeded"
+	KubernetesPodFailedPhase      = "Failed"
+
+	kubernetesDrainTimeout      = 5 * time.Minute
+	kubernetesDrainPollInterval = 10 * time.Second
+
+	KubernetesNodeReadyCondition = "Ready"
+	KubernetesConditionTrue      = "True"
+	KubernetesConditionUnknown   = "Unknown"
+)
+
+type IKubernetesService interface {
+	GetNodes(ctx context.Context, clusterUUID string) ([]resource.KubernetesNode, error)
+	GetNodePods(ctx context.Context, clusterUUID, nodeName string) ([]resource.KubernetesPod, error)
+	DrainNode(ctx context.Context, clusterUUID, nodeName string) error
+	DoRequest(ctx context.Context, clusterUUID, method, path string, body interface{}) (int, []byte, error)
+}
+
+type kubernetesService struct {
+	logger     *logrus.Logger
+	repository repository.IRepository
+}
+
+func NewKubernetesService(l *logrus.Logger, r repository.IRepository) IKubernetesService {
+	return &kubernetesService{
+		logger:     l,
+		repository: r,
+	}
+}
+
+type kubeconfigFile struct {
+	Clusters []struct {
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		User struct {
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Token                 string `yaml:"token"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+type kubernetesClient struct {
+	server string
+	token  string
+	client *http.Client
+}
+
+func (k *kubernetesService) newClient(ctx context.Context, clusterUUID string) (*kubernetesClient, error) {
+	kubeconfig, err := k.repository.Kubeconfig().GetKubeconfigByUUID(ctx, clusterUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	decodedKubeconfig, err := base64.StdEncoding.DecodeString(kubeconfig.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var kc kubeconfigFile
+	err = yaml.Unmarshal(decodedKubeconfig, &kc)
+	if err != nil {
+		return nil, err
+	}
+	if len(kc.Clusters) == 0 || len(kc.Users) == 0 {
+		return nil, fmt.Errorf("kubeconfig has no cluster or user entry")
+	}
+
+	cluster := kc.Clusters[0].Cluster
+	user := kc.Users[0].User
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cluster.InsecureSkipTLSVerify,
+	}
+
+	if cluster.CertificateAuthorityData != "" {
+		caData, err := base64.StdEncoding.DecodeString(cluster.CertificateAuthorityData)
+		if err != nil {
+			return nil, err
+		}
+		caPool := x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("failed to parse certificate authority data")
+		}
+		tlsConfig.RootCAs = caPool
+	}
+
+	if user.ClientCertificateData != "" && user.ClientKeyData != "" {
+		certData, err := base64.StdEncoding.DecodeString(user.ClientCertificateData)
+		if err != nil {
+			return nil, err
+		}
+		keyData, err := base64.StdEncoding.DecodeString(user.ClientKeyData)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certData, keyData)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &kubernetesClient{
+		server: strings.TrimSuffix(cluster.Server, "/"),
+		token:  user.Token,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:     tlsConfig,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			Timeout: time.Second * 30,
+		},
+	}, nil
+}
+
+// DoRequest sends a request to the kubernetes API of the cluster using the
+// stored kubeconfig and returns the status code together with the body.
+func (k *kubernetesService) DoRequest(ctx context.Context, clusterUUID, method, path string, body interface{}) (int, []byte, error) {
+	kc, err := k.newClient(ctx, clusterUUID)
+	if err != nil {
+		k.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to create kubernetes client")
+		return 0, nil, err
+	}
+
+	var reqBody io.Reader
+	contentType := "application/json"
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reqBody = bytes.NewBuffer(data)
+		if method == http.MethodPatch {
+			contentType = "application/strategic-merge-patch+json"
+		}
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", kc.server, path), reqBody)
+	if err != nil {
+		return 0, nil, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("Content-Type", contentType)
+	r.Header.Add("Accept", "application/json")
+	if kc.token != "" {
+		r.Header.Add("Authorization", fmt.Sprintf("Bearer %s", kc.token))
+	}
+
+	resp, err := kc.client.Do(r)
+	if err != nil {
+		k.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+			"path":        path,
+		}).Error("failed to send kubernetes request")
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+
+	return resp.StatusCode, respBody, nil
+}
+
+func (k *kubernetesService) GetNodes(ctx context.Context, clusterUUID string) ([]resource.KubernetesNode, error) {
+	statusCode, body, err := k.DoRequest(ctx, clusterUUID, http.MethodGet, KubernetesNodesPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		k.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+			"status_code": statusCode,
+		}).Error("failed to list kubernetes nodes")
+		return nil, fmt.Errorf("failed to list kubernetes nodes, status code: %v", statusCode)
+	}
+
+	var nodeList resource.KubernetesNodeListResponse
+	err = json.Unmarshal(body, &nodeList)
+	if err != nil {
+		k.logger.WithError(err).Error("failed to unmarshal response body")
+		return nil, err
+	}
+
+	return nodeList.Items, nil
+}
+
+func (k *kubernetesService) GetNodePods(ctx context.Context, clusterUUID, nodeName string) ([]resource.KubernetesPod, error) {
+	path := fmt.Sprintf("%s?fieldSelector=%s", KubernetesPodsPath, url.QueryEscape("spec.nodeName="+nodeName))
+	statusCode, body, err := k.DoRequest(ctx, clusterUUID, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		k.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+			"nodeName":    nodeName,
+			"status_code": statusCode,
+		}).Error("failed to list kubernetes pods")
+		return nil, fmt.Errorf("failed to list kubernetes pods, status code: %v", statusCode)
+	}
+
+	var podList resource.KubernetesPodListResponse
+	err = json.Unmarshal(body, &podList)
+	if err != nil {
+		k.logger.WithError(err).Error("failed to unmarshal response body")
+		return nil, err
+	}
+
+	return podList.Items, nil
+}
+
+// DrainNode cordons the node and evicts its workload pods through the eviction
+// API, so pod disruption budgets are respected. It waits until the pods are
+// gone or kubernetesDrainTimeout is reached.
+func (k *kubernetesService) DrainNode(ctx context.Context, clusterUUID, nodeName string) error {
+	cordon := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"unschedulable": true,
+		},
+	}
+	statusCode, _, err := k.DoRequest(ctx, clusterUUID, http.MethodPatch, fmt.Sprintf("%s/%s", KubernetesNodesPath, nodeName), cordon)
+	if err != nil {
+		return err
+	}
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if statusCode != http.StatusOK {
+		k.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+			"nodeName":    nodeName,
+			"status_code": statusCode,
+		}).Error("failed to cordon kubernetes node")
+		return fmt.Errorf("failed to cordon kubernetes node, status code: %v", statusCode)
+	}
+
+	deadline := time.Now().Add(kubernetesDrainTimeout)
+	for {
+		pods, err := k.GetNodePods(ctx, clusterUUID, nodeName)
+		if err != nil {
+			return err
+		}
+
+		remaining := 0
+		for _, pod := range pods {
+			if !IsKubernetesWorkloadPod(pod) {
+				continue
+			}
+			remaining++
+			err = k.evictPod(ctx, clusterUUID, pod)
+			if err != nil {
+				k.logger.WithError(err).WithFields(logrus.Fields{
+					"clusterUUID": clusterUUID,
+					"namespace":   pod.Metadata.Namespace,
+					"pod":         pod.Metadata.Name,
+				}).Warn("failed to evict pod")
+			}
+		}
+		if remaining == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out draining node %s, %d pods left", nodeName, remaining)
+		}
+		time.Sleep(kubernetesDrainPollInterval)
+	}
+}
+
+func (k *kubernetesService) evictPod(ctx context.Context, clusterUUID string, pod resource.KubernetesPod) error {
+	eviction := map[string]interface{}{
+		"apiVersion": "policy/v1",
+		"kind":       "Eviction",
+		"metadata": map[string]interface{}{
+			"name":      pod.Metadata.Name,
+			"namespace": pod.Metadata.Namespace,
+		},
+	}
+	path := fmt.Sprintf("api/v1/namespaces/%s/pods/%s/eviction", pod.Metadata.Namespace, pod.Metadata.Name)
+	statusCode, body, err := k.DoRequest(ctx, clusterUUID, http.MethodPost, path, eviction)
+	if err != nil {
+		return err
+	}
+	// 429 is returned while a pod disruption budget blocks the eviction, it is
+	// retried on the next poll.
+	if statusCode != http.StatusOK && statusCode != http.StatusCreated && statusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to evict pod, status code: %v, error msg: %v", statusCode, string(body))
+	}
+	return nil
+}
+
+// IsKubernetesWorkloadPod reports whether the pod would be lost when its node
+// is removed. DaemonSet, static (mirror) and completed pods are not counted.
+func IsKubernetesWorkloadPod(pod resource.KubernetesPod) bool {
+	if pod.Status.Phase == KubernetesPodSucceededPhase || pod.Status.Phase == KubernetesPodFailedPhase {
+		return false
+	}
+	if _, ok := pod.Metadata.Annotations[KubernetesMirrorPodAnnotation]; ok {
+		return false
+	}
+	for _, owner := range pod.Metadata.OwnerReferences {
+		if owner.Kind == KubernetesDaemonSetKind {
+			return false
+		}
+	}
+	return true
+}
+
+// IsKubernetesNodeReady returns the readiness of the node together with the
+// last time the Ready condition changed.
+func IsKubernetesNodeReady(node resource.KubernetesNode) (bool, time.Time) {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == KubernetesNodeReadyCondition {
+			return condition.Status == KubernetesConditionTrue, condition.LastTransitionTime
+		}
+	}
+	return false, time.Time{}
+}
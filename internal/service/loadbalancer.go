@@ -38,6 +38,12 @@ type ILoadbalancerService interface {
 	GetLoadBalancerListeners(ctx context.Context, authToken, loadBalancerID string) (resource.GetLoadBalancerListenersResponse, error)
 	DeleteLoadbalancerListeners(ctx context.Context, authToken, listenerID string) error
 	CheckLoadBalancerDeletingListeners(ctx context.Context, authToken, listenerID string) error
+	GetPool(ctx context.Context, authToken, poolID string) (resource.GetPoolResponse, error)
+	ListMembers(ctx context.Context, authToken, poolID string) (resource.ListMembersResponse, error)
+	DeleteMember(ctx context.Context, authToken, poolID, memberID string) error
+	ListProviders(ctx context.Context, authToken string) (resource.ListLoadBalancerProvidersResponse, error)
+	ListFlavors(ctx context.Context, authToken string) (resource.ListLoadBalancerFlavorsResponse, error)
+	GetFlavorProfile(ctx context.Context, authToken, flavorProfileID string) (resource.GetLoadBalancerFlavorProfileResponse, error)
 }
 
 type loadbalancerService struct {
@@ -870,3 +876,229 @@ func (lbc *loadbalancerService) DeleteLoadbalancer(ctx context.Context, authToke
 
 	return nil
 }
+
+func (lbc *loadbalancerService) GetPool(ctx context.Context, authToken, poolID string) (resource.GetPoolResponse, error) {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().LoadBalancerEndpoint, constants.ListenerPoolPath, poolID), nil)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID": poolID,
+		}).WithError(err).Error("failed to create request")
+		return resource.GetPoolResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	resp, err := lbc.client.Do(r)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID": poolID,
+		}).WithError(err).Error("failed to send request")
+		return resource.GetPoolResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID":     poolID,
+			"statusCode": resp.StatusCode,
+			"status":     resp.Status,
+		}).Error("failed to get load balancer pool")
+		return resource.GetPoolResponse{}, fmt.Errorf("failed to get load balancer pool, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.GetPoolResponse
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID": poolID,
+		}).WithError(err).Error("failed to decode response")
+		return resource.GetPoolResponse{}, err
+	}
+
+	return respDecoder, nil
+}
+
+func (lbc *loadbalancerService) ListMembers(ctx context.Context, authToken, poolID string) (resource.ListMembersResponse, error) {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s/members", config.GlobalConfig.GetEndpointsConfig().LoadBalancerEndpoint, constants.CreateMemberPath, poolID), nil)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID": poolID,
+		}).WithError(err).Error("failed to create request")
+		return resource.ListMembersResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	resp, err := lbc.client.Do(r)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID": poolID,
+		}).WithError(err).Error("failed to send request")
+		return resource.ListMembersResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID":     poolID,
+			"statusCode": resp.StatusCode,
+			"status":     resp.Status,
+		}).Error("failed to list pool members")
+		return resource.ListMembersResponse{}, fmt.Errorf("failed to list pool members, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.ListMembersResponse
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID": poolID,
+		}).WithError(err).Error("failed to decode response")
+		return resource.ListMembersResponse{}, err
+	}
+
+	return respDecoder, nil
+}
+
+func (lbc *loadbalancerService) DeleteMember(ctx context.Context, authToken, poolID, memberID string) error {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s/%s/members/%s", config.GlobalConfig.GetEndpointsConfig().LoadBalancerEndpoint, constants.CreateMemberPath, poolID, memberID), nil)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID":   poolID,
+			"memberID": memberID,
+		}).WithError(err).Error("failed to create request")
+		return err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	resp, err := lbc.client.Do(r)
+	if err != nil {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID":   poolID,
+			"memberID": memberID,
+		}).WithError(err).Error("failed to send request")
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		lbc.logger.WithFields(logrus.Fields{
+			"poolID":     poolID,
+			"memberID":   memberID,
+			"statusCode": resp.StatusCode,
+			"status":     resp.Status,
+		}).Error("failed to delete pool member")
+		return fmt.Errorf("failed to delete pool member, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
+
+func (lbc *loadbalancerService) ListProviders(ctx context.Context, authToken string) (resource.ListLoadBalancerProvidersResponse, error) {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", config.GlobalConfig.GetEndpointsConfig().LoadBalancerEndpoint, constants.LBProvidersPath), nil)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to create request")
+		return resource.ListLoadBalancerProvidersResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	resp, err := lbc.client.Do(r)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to send request")
+		return resource.ListLoadBalancerProvidersResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		lbc.logger.WithFields(logrus.Fields{
+			"statusCode": resp.StatusCode,
+			"status":     resp.Status,
+		}).Error("failed to list load balancer providers")
+		return resource.ListLoadBalancerProvidersResponse{}, fmt.Errorf("failed to list load balancer providers, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.ListLoadBalancerProvidersResponse
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to decode response")
+		return resource.ListLoadBalancerProvidersResponse{}, err
+	}
+
+	return respDecoder, nil
+}
+
+func (lbc *loadbalancerService) ListFlavors(ctx context.Context, authToken string) (resource.ListLoadBalancerFlavorsResponse, error) {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s", config.GlobalConfig.GetEndpointsConfig().LoadBalancerEndpoint, constants.LBFlavorsPath), nil)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to create request")
+		return resource.ListLoadBalancerFlavorsResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	resp, err := lbc.client.Do(r)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to send request")
+		return resource.ListLoadBalancerFlavorsResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		lbc.logger.WithFields(logrus.Fields{
+			"statusCode": resp.StatusCode,
+			"status":     resp.Status,
+		}).Error("failed to list load balancer flavors")
+		return resource.ListLoadBalancerFlavorsResponse{}, fmt.Errorf("failed to list load balancer flavors, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.ListLoadBalancerFlavorsResponse
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to decode response")
+		return resource.ListLoadBalancerFlavorsResponse{}, err
+	}
+
+	return respDecoder, nil
+}
+
+func (lbc *loadbalancerService) GetFlavorProfile(ctx context.Context, authToken, flavorProfileID string) (resource.GetLoadBalancerFlavorProfileResponse, error) {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().LoadBalancerEndpoint, constants.LBFlavorProfilesPath, flavorProfileID), nil)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to create request")
+		return resource.GetLoadBalancerFlavorProfileResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	resp, err := lbc.client.Do(r)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to send request")
+		return resource.GetLoadBalancerFlavorProfileResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		lbc.logger.WithFields(logrus.Fields{
+			"statusCode":      resp.StatusCode,
+			"status":          resp.Status,
+			"flavorProfileID": flavorProfileID,
+		}).Error("failed to get load balancer flavor profile")
+		return resource.GetLoadBalancerFlavorProfileResponse{}, fmt.Errorf("failed to get load balancer flavor profile, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.GetLoadBalancerFlavorProfileResponse
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		lbc.logger.WithError(err).Error("failed to decode response")
+		return resource.GetLoadBalancerFlavorProfileResponse{}, err
+	}
+
+	return respDecoder, nil
+}
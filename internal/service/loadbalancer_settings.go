@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/pkg/constants"
+	"gorm.io/datatypes"
+)
+
+const (
+	HealthMonitorTypeTCP   = "TCP"
+	HealthMonitorTypeHTTPS = "HTTPS"
+
+	LBAlgorithmRoundRobin       = "ROUND_ROBIN"
+	LBAlgorithmLeastConnections = "LEAST_CONNECTIONS"
+	LBAlgorithmSourceIP         = "SOURCE_IP"
+	LBAlgorithmSourceIPPort     = "SOURCE_IP_PORT"
+
+	SessionPersistenceSourceIP = "SOURCE_IP"
+
+	// LoadBalancerProviderOVN only supports TCP and UDP listeners, the
+	// SOURCE_IP_PORT algorithm and TCP health monitors.
+	LoadBalancerProviderOVN = "ovn"
+
+	// Octavia accepts between 1 and 10 retries before a member is marked up.
+	maxHealthMonitorRetries = 10
+	maxHealthMonitorDelay   = 300
+
+	// registerHealthMonitorDelay is the delay of the supervisor pool monitor,
+	// the LB_HEALTH_MONITOR_* delay only applies to the API pool.
+	registerHealthMonitorDelay = 30
+)
+
+var expectedCodesPattern = regexp.MustCompile(`^[1-5][0-9]{2}(-[1-5][0-9]{2})?(,[1-5][0-9]{2}(-[1-5][0-9]{2})?)*$`)
+
+// ResolveLoadBalancerSettings merges the per cluster overrides with the
+// configured defaults and validates the result.
+func ResolveLoadBalancerSettings(overrides *request.LoadBalancerSettings) (resource.LoadBalancerSettings, error) {
+	settings := defaultLoadBalancerSettings()
+	if overrides != nil {
+		if overrides.HealthMonitorType != "" {
+			settings.HealthMonitorType = strings.ToUpper(overrides.HealthMonitorType)
+		}
+		if overrides.HealthMonitorDelay != 0 {
+			settings.HealthMonitorDelay = overrides.HealthMonitorDelay
+		}
+		if overrides.HealthMonitorTimeout != 0 {
+			settings.HealthMonitorTimeout = overrides.HealthMonitorTimeout
+		}
+		if overrides.HealthMonitorMaxRetries != 0 {
+			settings.HealthMonitorMaxRetries = overrides.HealthMonitorMaxRetries
+		}
+		if overrides.HealthMonitorHTTPPath != "" {
+			settings.HealthMonitorHTTPPath = overrides.HealthMonitorHTTPPath
+		}
+		if overrides.HealthMonitorExpectedCodes != "" {
+			settings.HealthMonitorExpectedCodes = overrides.HealthMonitorExpectedCodes
+		}
+		if overrides.LBAlgorithm != "" {
+			settings.LBAlgorithm = strings.ToUpper(overrides.LBAlgorithm)
+		}
+		if overrides.SessionPersistence != "" {
+			settings.SessionPersistence = strings.ToUpper(overrides.SessionPersistence)
+		}
+	}
+
+	err := validateLoadBalancerSettings(settings)
+	if err != nil {
+		return resource.LoadBalancerSettings{}, fmt.Errorf("%s: %v", constants.ErrLoadBalancerSettingsInvalid, err)
+	}
+
+	return settings, nil
+}
+
+func defaultLoadBalancerSettings() resource.LoadBalancerSettings {
+	lbConfig := config.GlobalConfig.GetLoadBalancerConfig()
+	return resource.LoadBalancerSettings{
+		HealthMonitorType:          strings.ToUpper(lbConfig.HealthMonitorType),
+		HealthMonitorDelay:         lbConfig.Delay,
+		HealthMonitorTimeout:       lbConfig.Timeout,
+		HealthMonitorMaxRetries:    lbConfig.MaxRetries,
+		HealthMonitorHTTPPath:      lbConfig.HTTPPath,
+		HealthMonitorExpectedCodes: lbConfig.ExpectedCodes,
+		LBAlgorithm:                strings.ToUpper(lbConfig.Algorithm),
+		SessionPersistence:         strings.ToUpper(lbConfig.SessionPersistence),
+	}
+}
+
+func validateLoadBalancerSettings(settings resource.LoadBalancerSettings) error {
+	switch settings.HealthMonitorType {
+	case HealthMonitorTypeTCP, HealthMonitorTypeHTTPS:
+	default:
+		return fmt.Errorf("health monitor type must be %s or %s", HealthMonitorTypeTCP, HealthMonitorTypeHTTPS)
+	}
+	if settings.HealthMonitorDelay < 1 || settings.HealthMonitorDelay > maxHealthMonitorDelay {
+		return fmt.Errorf("health monitor delay must be between 1 and %d seconds", maxHealthMonitorDelay)
+	}
+	if settings.HealthMonitorTimeout < 1 || settings.HealthMonitorTimeout > settings.HealthMonitorDelay {
+		return fmt.Errorf("health monitor timeout must be between 1 second and the delay")
+	}
+	if settings.HealthMonitorMaxRetries < 1 || settings.HealthMonitorMaxRetries > maxHealthMonitorRetries {
+		return fmt.Errorf("health monitor max retries must be between 1 and %d", maxHealthMonitorRetries)
+	}
+	if settings.HealthMonitorType == HealthMonitorTypeHTTPS {
+		if !strings.HasPrefix(settings.HealthMonitorHTTPPath, "/") {
+			return fmt.Errorf("health monitor http path must start with /")
+		}
+		if !expectedCodesPattern.MatchString(settings.HealthMonitorExpectedCodes) {
+			return fmt.Errorf("health monitor expected codes must be a code, a list or a range such as 200, 200,202 or 200-204")
+		}
+	}
+	switch settings.LBAlgorithm {
+	case LBAlgorithmRoundRobin, LBAlgorithmLeastConnections, LBAlgorithmSourceIP, LBAlgorithmSourceIPPort:
+	default:
+		return fmt.Errorf("lb algorithm must be one of %s, %s, %s, %s", LBAlgorithmRoundRobin, LBAlgorithmLeastConnections, LBAlgorithmSourceIP, LBAlgorithmSourceIPPort)
+	}
+	// The control plane pools are TCP pools, cookie based persistence is not
+	// available for them.
+	if settings.SessionPersistence != "" && settings.SessionPersistence != SessionPersistenceSourceIP {
+		return fmt.Errorf("session persistence must be empty or %s", SessionPersistenceSourceIP)
+	}
+	return nil
+}
+
+// getLoadBalancerSettings returns the stored settings of a cluster, clusters
+// created before the settings were stored report the configured defaults.
+func getLoadBalancerSettings(settingsJSON datatypes.JSON) resource.LoadBalancerSettings {
+	if settingsJSON == nil {
+		return defaultLoadBalancerSettings()
+	}
+	settings := resource.LoadBalancerSettings{}
+	err := json.Unmarshal(settingsJSON, &settings)
+	if err != nil {
+		return defaultLoadBalancerSettings()
+	}
+	return settings
+}
+
+func loadBalancerPool(settings resource.LoadBalancerSettings, name, listenerID string) request.CreatePoolRequest {
+	pool := request.CreatePoolRequest{
+		Pool: request.Pool{
+			Protocol:     "TCP",
+			AdminStateUp: true,
+			ListenerID:   listenerID,
+			Name:         name,
+			LBAlgorithm:  settings.LBAlgorithm,
+		},
+	}
+	if settings.SessionPersistence != "" {
+		pool.Pool.SessionPersistence = &request.SessionPersistence{Type: settings.SessionPersistence}
+	}
+	return pool
+}
+
+func apiHealthMonitor(settings resource.LoadBalancerSettings, name, poolID string) request.CreateHealthMonitorHTTPRequest {
+	healthMonitor := request.CreateHealthMonitorHTTPRequest{
+		HealthMonitor: request.HealthMonitorHTTP{
+			Name:           name,
+			AdminStateUp:   true,
+			PoolID:         poolID,
+			MaxRetries:     strconv.Itoa(settings.HealthMonitorMaxRetries),
+			Delay:          strconv.Itoa(settings.HealthMonitorDelay),
+			TimeOut:        strconv.Itoa(settings.HealthMonitorTimeout),
+			Type:           settings.HealthMonitorType,
+			MaxRetriesDown: 3,
+		},
+	}
+	if settings.HealthMonitorType == HealthMonitorTypeHTTPS {
+		healthMonitor.HealthMonitor.HTTPMethod = "GET"
+		healthMonitor.HealthMonitor.URLPath = settings.HealthMonitorHTTPPath
+		healthMonitor.HealthMonitor.ExpectedCodes = settings.HealthMonitorExpectedCodes
+	}
+	return healthMonitor
+}
+
+// registerHealthMonitor checks the rke2 supervisor port, which does not serve
+// the kubernetes health endpoints, so it always uses a TCP check every
+// registerHealthMonitorDelay seconds.
+func registerHealthMonitor(settings resource.LoadBalancerSettings, name, poolID string) request.CreateHealthMonitorTCPRequest {
+	timeout := settings.HealthMonitorTimeout
+	if timeout > registerHealthMonitorDelay {
+		timeout = registerHealthMonitorDelay
+	}
+	return request.CreateHealthMonitorTCPRequest{
+		HealthMonitor: request.HealthMonitorTCP{
+			Name:           name,
+			AdminStateUp:   true,
+			PoolID:         poolID,
+			MaxRetries:     strconv.Itoa(settings.HealthMonitorMaxRetries),
+			Delay:          strconv.Itoa(registerHealthMonitorDelay),
+			TimeOut:        strconv.Itoa(timeout),
+			Type:           HealthMonitorTypeTCP,
+			MaxRetriesDown: 3,
+		},
+	}
+}
+
+// ResolveLoadBalancerProvider validates the requested provider and flavor
+// against the load balancer API. An empty provider falls back to the
+// configured LOADBALANCER_PROVIDER. The flavor profile of the flavor must
+// belong to the provider, Octavia only shows flavor profiles to admins by
+// default so the check is skipped when the profile is forbidden.
+func ResolveLoadBalancerProvider(ctx context.Context, lbs ILoadbalancerService, token, provider, flavorID string, settings resource.LoadBalancerSettings) (string, error) {
+	if provider == "" {
+		provider = config.GlobalConfig.GetOpenStackApiConfig().LoadbalancerProvider
+	} else {
+		providers, err := lbs.ListProviders(ctx, token)
+		if err != nil {
+			return "", err
+		}
+		found := false
+		for _, p := range providers.Providers {
+			if p.Name == provider {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("%s: provider %s is not available", constants.ErrLoadBalancerProviderInvalid, provider)
+		}
+	}
+
+	if flavorID != "" {
+		flavors, err := lbs.ListFlavors(ctx, token)
+		if err != nil {
+			return "", err
+		}
+		var selected *resource.LoadBalancerFlavor
+		for i, flavor := range flavors.Flavors {
+			if flavor.ID == flavorID && flavor.Enabled {
+				selected = &flavors.Flavors[i]
+				break
+			}
+		}
+		if selected == nil {
+			return "", fmt.Errorf("%s: flavor %s is not available", constants.ErrLoadBalancerProviderInvalid, flavorID)
+		}
+
+		if selected.FlavorProfileID != "" {
+			flavorProfile, err := lbs.GetFlavorProfile(ctx, token, selected.FlavorProfileID)
+			if err != nil && !strings.Contains(err.Error(), "403") {
+				return "", err
+			}
+			if err == nil && flavorProfile.FlavorProfile.ProviderName != provider {
+				return "", fmt.Errorf("%s: flavor %s belongs to provider %s", constants.ErrLoadBalancerProviderInvalid, flavorID, flavorProfile.FlavorProfile.ProviderName)
+			}
+		}
+	}
+
+	if provider == LoadBalancerProviderOVN {
+		if settings.LBAlgorithm != LBAlgorithmSourceIPPort || settings.HealthMonitorType != HealthMonitorTypeTCP || settings.SessionPersistence != "" {
+			return "", fmt.Errorf("%s: provider %s requires the %s algorithm, %s health monitors and no session persistence", constants.ErrLoadBalancerProviderInvalid, provider, LBAlgorithmSourceIPPort, HealthMonitorTypeTCP)
+		}
+	}
+
+	return provider, nil
+}
+
+// clusterLoadBalancerProvider returns the provider of the load balancers of a
+// cluster, clusters created before it was stored use the configured one.
+func clusterLoadBalancerProvider(cluster *model.Cluster) string {
+	if cluster.ClusterLBProvider != "" {
+		return cluster.ClusterLBProvider
+	}
+	return config.GlobalConfig.GetOpenStackApiConfig().LoadbalancerProvider
+}
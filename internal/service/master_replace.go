@@ -0,0 +1,544 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/pkg/constants"
+)
+
+const (
+	masterAPIPoolSuffix      = "-api-pool"
+	masterRegisterPoolSuffix = "-register-pool"
+	masterAPIPort            = 6443
+	masterRegisterPort       = 9345
+	masterVolumeSize         = 50
+	// The new master gets 20 minutes to join the cluster and become ready.
+	masterReadyWaitIterations = 80
+)
+
+// ReplaceMaster validates the replacement request and replaces the given master
+// server of the cluster in the background. The cluster stays in the Updating
+// status until the new master has joined the load balancer pools and the old
+// master is removed.
+func (c *clusterService) ReplaceMaster(ctx context.Context, authToken, clusterID, serverID string) (resource.ReplaceMasterResponse, error) {
+	token := strings.Clone(authToken)
+
+	cluster, err := c.repository.Cluster().GetClusterByUUID(ctx, clusterID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to get cluster")
+		return resource.ReplaceMasterResponse{}, err
+	}
+
+	if cluster == nil || cluster.ClusterProjectUUID == "" {
+		c.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to get cluster")
+		return resource.ReplaceMasterResponse{}, fmt.Errorf("failed to get cluster")
+	}
+
+	err = c.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to check auth token")
+		return resource.ReplaceMasterResponse{}, err
+	}
+
+	if cluster.ClusterStatus != ActiveClusterStatus {
+		c.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to replace master, cluster is not active")
+		return resource.ReplaceMasterResponse{}, fmt.Errorf("failed to replace master, cluster is not active")
+	}
+
+	masterNodeGroups, err := c.repository.NodeGroups().GetNodeGroupsByClusterUUID(ctx, cluster.ClusterUUID, NodeGroupMasterType, "")
+	if err != nil || len(masterNodeGroups) == 0 {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to get master node group")
+		return resource.ReplaceMasterResponse{}, fmt.Errorf("failed to get master node group")
+	}
+	masterNodeGroup := masterNodeGroups[0]
+
+	members, err := c.computeService.GetServerGroupMemberList(ctx, token, masterNodeGroup.NodeGroupUUID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to get master server group members")
+		return resource.ReplaceMasterResponse{}, err
+	}
+
+	isMaster := false
+	otherMasters := []string{}
+	for _, member := range members.Members {
+		if member == serverID {
+			isMaster = true
+			continue
+		}
+		otherMasters = append(otherMasters, member)
+	}
+	if !isMaster {
+		c.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+			"serverID":    serverID,
+		}).Error("failed to replace master, server is not a master of the cluster")
+		return resource.ReplaceMasterResponse{}, fmt.Errorf("failed to replace master, server is not a master of the cluster")
+	}
+
+	err = c.checkRemainingMastersReady(ctx, token, cluster.ClusterUUID, otherMasters)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+			"serverID":    serverID,
+		}).Error("failed to replace master")
+		return resource.ReplaceMasterResponse{}, err
+	}
+
+	oldServer, err := c.computeService.GetInstancesDetail(ctx, token, serverID)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+			"serverID":    serverID,
+		}).Error("failed to get server detail")
+		return resource.ReplaceMasterResponse{}, err
+	}
+
+	cluster.ClusterStatus = UpdatingClusterStatus
+	err = c.repository.Cluster().UpdateCluster(ctx, cluster)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to update cluster")
+		return resource.ReplaceMasterResponse{}, err
+	}
+
+	newServerName := fmt.Sprintf("%s-master-%s", cluster.ClusterName, uuid.New().String()[:8])
+
+	err = c.CreateAuditLog(ctx, cluster.ClusterUUID, cluster.ClusterProjectUUID, fmt.Sprintf("Master %s replacement started", oldServer.OpenstackServers.Name))
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterID,
+		}).Error("failed to create audit log")
+	}
+
+	go c.replaceMaster(context.Background(), token, cluster, masterNodeGroup, oldServer.OpenstackServers, newServerName)
+
+	return resource.ReplaceMasterResponse{
+		ClusterUUID:      cluster.ClusterUUID,
+		ReplacedServerID: serverID,
+		NewServerName:    newServerName,
+		ClusterStatus:    UpdatingClusterStatus,
+	}, nil
+}
+
+// checkRemainingMastersReady makes sure at least one of the other masters is
+// ready in kubernetes, otherwise etcd has no quorum for the new member to join.
+func (c *clusterService) checkRemainingMastersReady(ctx context.Context, token, clusterUUID string, serverIDs []string) error {
+	if len(serverIDs) == 0 {
+		return fmt.Errorf("failed to replace master, cluster has no other master to keep etcd quorum")
+	}
+
+	kubernetesNodes, err := c.kubernetesService.GetNodes(ctx, clusterUUID)
+	if err != nil {
+		return err
+	}
+
+	for _, serverID := range serverIDs {
+		server, err := c.computeService.GetInstancesDetail(ctx, token, serverID)
+		if err != nil {
+			continue
+		}
+		for _, node := range kubernetesNodes {
+			if !strings.EqualFold(node.Metadata.Name, server.OpenstackServers.Name) {
+				continue
+			}
+			if ready, _ := IsKubernetesNodeReady(node); ready {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("failed to replace master, none of the other masters is ready")
+}
+
+// replaceMaster creates the new master first and only removes the old one
+// once the new master is ready and serves in the load balancer pools, so etcd
+// keeps its fault tolerance while the replacement is running.
+func (c *clusterService) replaceMaster(ctx context.Context, token string, cluster *model.Cluster, masterNodeGroup model.NodeGroups, oldServer resource.OpenstackServer, newServerName string) {
+	clusterError := constants.ErrMasterReplaceFailed
+	// Until the old master is touched a failure leaves the cluster as it was,
+	// the half created new master is removed and the cluster stays active.
+	oldMasterRemoved := false
+	newServerID := ""
+	newPortID := ""
+	fail := func(message string, err error) {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+			"serverID":    oldServer.ID,
+		}).Error(message)
+		c.logClusterErrorFiltered(ctx, cluster.ClusterUUID, clusterError, "master_replacement", err)
+
+		err = c.CreateAuditLog(ctx, cluster.ClusterUUID, cluster.ClusterProjectUUID, fmt.Sprintf("Master %s replacement failed", oldServer.Name))
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to create audit log")
+		}
+
+		cluster.ClusterStatus = ErrorClusterStatus
+		if !oldMasterRemoved {
+			c.removeReplacementMaster(ctx, token, cluster, newServerName, newServerID, newPortID)
+			cluster.ClusterStatus = ActiveClusterStatus
+		}
+		err = c.repository.Cluster().UpdateCluster(ctx, cluster)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to update cluster")
+		}
+	}
+
+	masterPools, err := c.getMasterPools(ctx, token, cluster.ClusterLoadbalancerUUID)
+	if err != nil {
+		fail("failed to get master load balancer pools", err)
+		return
+	}
+
+	subnetIDs := []string{}
+	err = json.Unmarshal(cluster.ClusterSubnets, &subnetIDs)
+	if err != nil || len(subnetIDs) == 0 {
+		fail("failed to unmarshal cluster subnets", err)
+		return
+	}
+	networkIDResp, err := c.networkService.GetNetworkID(ctx, token, subnetIDs[0])
+	if err != nil {
+		fail("failed to get networkId", err)
+		return
+	}
+	clusterSubnets, err := c.networkService.GetClusterSubnets(ctx, token, subnetIDs)
+	if err != nil {
+		fail("failed to get cluster subnets", err)
+		return
+	}
+
+	portResp, err := c.networkService.CreateNetworkPort(ctx, token, request.CreateNetworkPortRequest{
+		Port: request.Port{
+			Name:           fmt.Sprintf("%s-port", newServerName),
+			NetworkID:      networkIDResp.Subnet.NetworkID,
+			AdminStateUp:   true,
+			FixedIps:       clusterSubnets.FixedIps(),
+			SecurityGroups: []string{masterNodeGroup.NodeGroupSecurityGroup, cluster.ClusterSharedSecurityGroup},
+		},
+	})
+	if err != nil {
+		fail("failed to create network port", err)
+		return
+	}
+	newPortID = portResp.Port.ID
+
+	userDataExtensions, err := getUserDataExtensions(cluster.ClusterUserData)
+	if err != nil {
+		fail("failed to unmarshal user data extensions", err)
+		return
+	}
+
+	rke2InitScript, err := GenerateUserDataFromTemplate("false",
+		MasterServerType,
+		cluster.ClusterRegisterToken,
+		cluster.ClusterEndpoint,
+		cluster.ClusterVersion,
+		cluster.ClusterName,
+		cluster.ClusterUUID,
+		"",
+		config.GlobalConfig.GetWebConfig().Endpoint,
+		token,
+		config.GlobalConfig.GetVkeAgentConfig().VkeAgentVersion,
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+		getClusterTLSSANs(cluster.ClusterTLSSANs),
+		clusterRKE2NetworkSettings(cluster),
+		userDataExtensions,
+	)
+	if err != nil {
+		fail("failed to generate user data from template", err)
+		return
+	}
+
+	masterSecurityGroup, err := c.networkService.GetSecurityGroupByID(ctx, token, masterNodeGroup.NodeGroupSecurityGroup)
+	if err != nil {
+		fail("failed to get master security group", err)
+		return
+	}
+	sharedSecurityGroup, err := c.networkService.GetSecurityGroupByID(ctx, token, cluster.ClusterSharedSecurityGroup)
+	if err != nil {
+		fail("failed to get shared security group", err)
+		return
+	}
+
+	imageRef := nodeGroupImageRef(masterNodeGroup)
+	serverResp, err := c.computeService.CreateCompute(ctx, token, request.CreateComputeRequest{
+		Server: request.Server{
+			Name:             newServerName,
+			ImageRef:         imageRef,
+			FlavorRef:        masterNodeGroup.NodeFlavorUUID,
+			KeyName:          cluster.ClusterNodeKeypairName,
+			AvailabilityZone: "nova",
+			SecurityGroups: []request.SecurityGroups{
+				{Name: masterSecurityGroup.SecurityGroup.Name},
+				{Name: sharedSecurityGroup.SecurityGroup.Name},
+			},
+			BlockDeviceMappingV2: []request.BlockDeviceMappingV2{
+				{
+					BootIndex:           0,
+					DestinationType:     "volume",
+					DeleteOnTermination: true,
+					SourceType:          "image",
+					UUID:                imageRef,
+					VolumeSize:          masterVolumeSize,
+				},
+			},
+			Networks: []request.Networks{
+				{Port: portResp.Port.ID},
+			},
+			UserData: Base64Encoder(rke2InitScript),
+		},
+		SchedulerHints: request.SchedulerHints{
+			Group: masterNodeGroup.NodeGroupUUID,
+		},
+	})
+	if err != nil {
+		fail("failed to create compute", err)
+		return
+	}
+	newServerID = serverResp.Server.ID
+
+	err = c.waitForKubernetesNodeReady(ctx, cluster.ClusterUUID, newServerName)
+	if err != nil {
+		fail("failed to wait for the new master to become ready", err)
+		return
+	}
+
+	for poolID, protocolPort := range masterPools {
+		_, err = c.loadbalancerService.CheckLoadBalancerStatus(ctx, token, cluster.ClusterLoadbalancerUUID)
+		if err != nil {
+			fail("failed to check load balancer status", err)
+			return
+		}
+		err = c.loadbalancerService.CreateMember(ctx, token, poolID, request.AddMemberRequest{
+			Member: request.Member{
+				Name:         newServerName,
+				AdminStateUp: true,
+				SubnetID:     PrimaryFixedIP(portResp.Port.FixedIps).SubnetID,
+				Address:      PrimaryFixedIP(portResp.Port.FixedIps).IpAddress,
+				ProtocolPort: protocolPort,
+				Backup:       false,
+			},
+		})
+		if err != nil {
+			fail("failed to create member", err)
+			return
+		}
+	}
+
+	// The new master serves now, from here on the old master is removed and a
+	// failure needs manual attention.
+	oldMasterRemoved = true
+
+	oldAddresses := map[string]bool{}
+	for _, addresses := range oldServer.Addresses {
+		for _, address := range addresses {
+			oldAddresses[address.Addr] = true
+		}
+	}
+	for poolID := range masterPools {
+		poolMembers, err := c.loadbalancerService.ListMembers(ctx, token, poolID)
+		if err != nil {
+			fail("failed to list pool members", err)
+			return
+		}
+		for _, member := range poolMembers.Members {
+			if !oldAddresses[member.Address] && member.Name != oldServer.Name {
+				continue
+			}
+			_, err = c.loadbalancerService.CheckLoadBalancerStatus(ctx, token, cluster.ClusterLoadbalancerUUID)
+			if err != nil {
+				fail("failed to check load balancer status", err)
+				return
+			}
+			err = c.loadbalancerService.DeleteMember(ctx, token, poolID, member.ID)
+			if err != nil {
+				fail("failed to delete pool member", err)
+				return
+			}
+		}
+	}
+
+	oldPorts, err := c.networkService.GetComputeNetworkPorts(ctx, token, oldServer.ID)
+	if err != nil {
+		fail("failed to get compute network ports", err)
+		return
+	}
+	err = c.computeService.DeleteCompute(ctx, token, oldServer.ID)
+	if err != nil {
+		fail("failed to delete compute", err)
+		return
+	}
+	for _, portID := range oldPorts.Ports {
+		err = c.networkService.DeleteNetworkPort(ctx, token, portID)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+				"portID":      portID,
+			}).Warn("failed to delete network port of replaced master")
+		}
+	}
+
+	// RKE2 removes the etcd member of a server node once its node object is
+	// deleted, so the dead member is cleaned up through the kubernetes API.
+	err = c.deleteKubernetesNode(ctx, cluster.ClusterUUID, oldServer.Name)
+	if err != nil {
+		clusterError = constants.ErrEtcdMemberRemoveFailed
+		fail("failed to remove etcd member of replaced master", err)
+		return
+	}
+
+	cluster.ClusterStatus = ActiveClusterStatus
+	err = c.repository.Cluster().UpdateCluster(ctx, cluster)
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to update cluster")
+	}
+
+	err = c.CreateAuditLog(ctx, cluster.ClusterUUID, cluster.ClusterProjectUUID, fmt.Sprintf("Master %s replaced by %s", oldServer.Name, newServerName))
+	if err != nil {
+		c.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to create audit log")
+	}
+}
+
+// waitForKubernetesNodeReady waits until the node of the server has joined
+// the cluster and is ready.
+func (c *clusterService) waitForKubernetesNodeReady(ctx context.Context, clusterUUID, serverName string) error {
+	waitIterator := 0
+	waitSeconds := 15
+	for waitIterator < masterReadyWaitIterations {
+		time.Sleep(time.Duration(waitSeconds) * time.Second)
+		waitIterator++
+
+		kubernetesNodes, err := c.kubernetesService.GetNodes(ctx, clusterUUID)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+			}).Warn("failed to get kubernetes nodes")
+			continue
+		}
+		for _, node := range kubernetesNodes {
+			if !strings.EqualFold(node.Metadata.Name, serverName) {
+				continue
+			}
+			if ready, _ := IsKubernetesNodeReady(node); ready {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("node %s did not become ready", serverName)
+}
+
+// removeReplacementMaster removes a new master which did not make it into
+// the cluster, the old master keeps serving.
+func (c *clusterService) removeReplacementMaster(ctx context.Context, token string, cluster *model.Cluster, serverName, serverID, portID string) {
+	if serverID != "" {
+		err := c.computeService.DeleteCompute(ctx, token, serverID)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+				"serverID":    serverID,
+			}).Error("failed to delete compute of replacement master")
+		}
+		err = c.deleteKubernetesNode(ctx, cluster.ClusterUUID, serverName)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+				"serverName":  serverName,
+			}).Error("failed to delete kubernetes node of replacement master")
+		}
+	}
+	if portID != "" {
+		err := c.networkService.DeleteNetworkPort(ctx, token, portID)
+		if err != nil {
+			c.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+				"portID":      portID,
+			}).Error("failed to delete network port of replacement master")
+		}
+	}
+}
+
+// getMasterPools returns the api and register pools of the cluster load
+// balancer together with the port their members listen on.
+func (c *clusterService) getMasterPools(ctx context.Context, token, loadBalancerID string) (map[string]int, error) {
+	pools, err := c.loadbalancerService.GetLoadBalancerPools(ctx, token, loadBalancerID)
+	if err != nil {
+		return nil, err
+	}
+
+	masterPools := map[string]int{}
+	for _, poolID := range pools.Pools {
+		pool, err := c.loadbalancerService.GetPool(ctx, token, poolID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasSuffix(pool.Pool.Name, masterAPIPoolSuffix):
+			masterPools[poolID] = masterAPIPort
+		case strings.HasSuffix(pool.Pool.Name, masterRegisterPoolSuffix):
+			masterPools[poolID] = masterRegisterPort
+		}
+	}
+
+	if len(masterPools) != 2 {
+		return nil, fmt.Errorf("failed to find api and register pools of load balancer %s", loadBalancerID)
+	}
+
+	return masterPools, nil
+}
+
+func (c *clusterService) deleteKubernetesNode(ctx context.Context, clusterUUID, serverName string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	statusCode, body, err := c.kubernetesService.DoRequest(ctx, clusterUUID, http.MethodDelete, fmt.Sprintf("%s/%s", KubernetesNodesPath, strings.ToLower(serverName)), nil)
+	if err != nil {
+		return err
+	}
+
+	switch statusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound:
+		return nil
+	}
+
+	return fmt.Errorf("failed to delete kubernetes node, status code: %v, error msg: %v", statusCode, string(body))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"strings"
@@ -33,6 +34,15 @@ type INetworkService interface {
 	GetSubnetByID(ctx context.Context, authToken, subnetID string) (resource.SubnetResponse, error)
 	GetComputeNetworkPorts(ctx context.Context, authToken, instanceID string) (resource.NetworkPortsResponse, error)
 	GetSecurityGroupPorts(ctx context.Context, authToken, securityGroupID string) (resource.NetworkPortsResponse, error)
+	CreateNetwork(ctx context.Context, authToken string, req request.CreateNetworkRequest) (resource.CreateNetworkResponse, error)
+	CreateSubnet(ctx context.Context, authToken string, req request.CreateSubnetRequest) (resource.SubnetResponse, error)
+	CreateRouter(ctx context.Context, authToken string, req request.CreateRouterRequest) (resource.CreateRouterResponse, error)
+	AddRouterInterface(ctx context.Context, authToken, routerID, subnetID string) error
+	RemoveRouterInterface(ctx context.Context, authToken, routerID, subnetID string) error
+	DeleteRouter(ctx context.Context, authToken, routerID string) error
+	DeleteSubnet(ctx context.Context, authToken, subnetID string) error
+	DeleteNetwork(ctx context.Context, authToken, networkID string) error
+	GetClusterSubnets(ctx context.Context, authToken string, subnetIDs []string) (ClusterSubnets, error)
 }
 
 type networkService struct {
@@ -595,3 +605,492 @@ func (ns *networkService) GetSecurityGroupPorts(ctx context.Context, authToken,
 
 	return result, nil
 }
+
+func (ns *networkService) CreateNetwork(ctx context.Context, authToken string, req request.CreateNetworkRequest) (resource.CreateNetworkResponse, error) {
+	token := strings.Clone(authToken)
+	data, err := json.Marshal(req)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to marshal request")
+		return resource.CreateNetworkResponse{}, err
+	}
+	r, err := http.NewRequest("POST", fmt.Sprintf("%s/%s", config.GlobalConfig.GetEndpointsConfig().NetworkEndpoint, constants.NetworksPath), bytes.NewBuffer(data))
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to create request")
+		return resource.CreateNetworkResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+	r.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to send request")
+		return resource.CreateNetworkResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		ns.logger.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"error_msg":   resp.Status,
+		}).Error("failed to create network")
+		return resource.CreateNetworkResponse{}, fmt.Errorf("failed to create network, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.CreateNetworkResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to decode response")
+		return resource.CreateNetworkResponse{}, err
+	}
+
+	return respDecoder, nil
+}
+
+func (ns *networkService) CreateSubnet(ctx context.Context, authToken string, req request.CreateSubnetRequest) (resource.SubnetResponse, error) {
+	token := strings.Clone(authToken)
+	data, err := json.Marshal(req)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to marshal request")
+		return resource.SubnetResponse{}, err
+	}
+	r, err := http.NewRequest("POST", fmt.Sprintf("%s/%s", config.GlobalConfig.GetEndpointsConfig().NetworkEndpoint, constants.SubnetsPath), bytes.NewBuffer(data))
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to create request")
+		return resource.SubnetResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+	r.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to send request")
+		return resource.SubnetResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		ns.logger.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"error_msg":   resp.Status,
+		}).Error("failed to create subnet")
+		return resource.SubnetResponse{}, fmt.Errorf("failed to create subnet, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.SubnetResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to decode response")
+		return resource.SubnetResponse{}, err
+	}
+
+	return respDecoder, nil
+}
+
+func (ns *networkService) CreateRouter(ctx context.Context, authToken string, req request.CreateRouterRequest) (resource.CreateRouterResponse, error) {
+	token := strings.Clone(authToken)
+	data, err := json.Marshal(req)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to marshal request")
+		return resource.CreateRouterResponse{}, err
+	}
+	r, err := http.NewRequest("POST", fmt.Sprintf("%s/%s", config.GlobalConfig.GetEndpointsConfig().NetworkEndpoint, constants.RoutersPath), bytes.NewBuffer(data))
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to create request")
+		return resource.CreateRouterResponse{}, err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+	r.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to send request")
+		return resource.CreateRouterResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		ns.logger.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"error_msg":   resp.Status,
+		}).Error("failed to create router")
+		return resource.CreateRouterResponse{}, fmt.Errorf("failed to create router, status code: %v, error msg: %v", resp.StatusCode, resp.Status)
+	}
+
+	var respDecoder resource.CreateRouterResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&respDecoder)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to decode response")
+		return resource.CreateRouterResponse{}, err
+	}
+
+	return respDecoder, nil
+}
+
+func (ns *networkService) AddRouterInterface(ctx context.Context, authToken, routerID, subnetID string) error {
+	return ns.updateRouterInterface(ctx, authToken, routerID, subnetID, "add_router_interface")
+}
+
+func (ns *networkService) RemoveRouterInterface(ctx context.Context, authToken, routerID, subnetID string) error {
+	return ns.updateRouterInterface(ctx, authToken, routerID, subnetID, "remove_router_interface")
+}
+
+func (ns *networkService) updateRouterInterface(ctx context.Context, authToken, routerID, subnetID, action string) error {
+	token := strings.Clone(authToken)
+	data, err := json.Marshal(request.RouterInterfaceRequest{SubnetID: subnetID})
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to marshal request")
+		return err
+	}
+	r, err := http.NewRequest("PUT", fmt.Sprintf("%s/%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().NetworkEndpoint, constants.RoutersPath, routerID, action), bytes.NewBuffer(data))
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to create request")
+		return err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+	r.Header.Add("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to send request")
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		ns.logger.WithFields(logrus.Fields{
+			"status_code": resp.StatusCode,
+			"error_msg":   resp.Status,
+			"routerID":    routerID,
+			"subnetID":    subnetID,
+		}).Errorf("failed to %s", strings.ReplaceAll(action, "_", " "))
+		return fmt.Errorf("failed to %s, status code: %v, error msg: %v", strings.ReplaceAll(action, "_", " "), resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
+
+func (ns *networkService) DeleteRouter(ctx context.Context, authToken, routerID string) error {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().NetworkEndpoint, constants.RoutersPath, routerID), nil)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to create request")
+		return err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to send request")
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete %s router, status code: %v, error msg: %v", routerID, resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
+
+func (ns *networkService) DeleteSubnet(ctx context.Context, authToken, subnetID string) error {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().NetworkEndpoint, constants.SubnetsPath, subnetID), nil)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to create request")
+		return err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to send request")
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete %s subnet, status code: %v, error msg: %v", subnetID, resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
+
+func (ns *networkService) DeleteNetwork(ctx context.Context, authToken, networkID string) error {
+	token := strings.Clone(authToken)
+	r, err := http.NewRequest("DELETE", fmt.Sprintf("%s/%s/%s", config.GlobalConfig.GetEndpointsConfig().NetworkEndpoint, constants.NetworksPath, networkID), nil)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to create request")
+		return err
+	}
+	r.Header = make(http.Header)
+	r.Header.Add("X-Auth-Token", token)
+
+	client := &http.Client{}
+	resp, err := client.Do(r)
+	if err != nil {
+		ns.logger.WithError(err).Error("failed to send request")
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("failed to delete %s network, status code: %v, error msg: %v", networkID, resp.StatusCode, resp.Status)
+	}
+
+	return nil
+}
+
+// ResolveClusterNetworkCIDR returns the CIDR of the subnet vke creates for a
+// cluster, CLUSTER_NETWORK_CIDR when the request does not set one. Clusters
+// which do not ask for a network must bring their own subnets.
+func ResolveClusterNetworkCIDR(req request.CreateClusterRequest) (string, error) {
+	if req.CreateNetwork == nil {
+		if len(req.SubnetIDs) == 0 {
+			return "", fmt.Errorf("%s: subnetIds are required when createNetwork is not set", constants.ErrClusterSubnetInvalid)
+		}
+		return "", nil
+	}
+	if len(req.SubnetIDs) > 0 {
+		return "", fmt.Errorf("%s: subnetIds and createNetwork cannot be set together", constants.ErrClusterSubnetInvalid)
+	}
+
+	cidr := req.CreateNetwork.CIDR
+	if cidr == "" {
+		cidr = config.GlobalConfig.GetClusterNetworkConfig().CIDR
+	}
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil || ip.To4() == nil {
+		return "", fmt.Errorf("%s: %s is not a valid ipv4 cidr", constants.ErrClusterSubnetInvalid, cidr)
+	}
+	return ipNet.String(), nil
+}
+
+// ClusterSubnets groups the subnets of a cluster by IP version. Clusters with
+// subnets of both versions are dual-stack.
+type ClusterSubnets struct {
+	IPv4  []string
+	IPv6  []string
+	CIDRs []string
+}
+
+func (ns *networkService) GetClusterSubnets(ctx context.Context, authToken string, subnetIDs []string) (ClusterSubnets, error) {
+	clusterSubnets := ClusterSubnets{}
+	for _, subnetID := range subnetIDs {
+		subnet, err := ns.GetSubnetByID(ctx, authToken, subnetID)
+		if err != nil {
+			return ClusterSubnets{}, err
+		}
+		clusterSubnets.CIDRs = append(clusterSubnets.CIDRs, subnet.Subnet.CIDR)
+		if subnet.Subnet.IPVersion == 6 {
+			clusterSubnets.IPv6 = append(clusterSubnets.IPv6, subnetID)
+		} else {
+			clusterSubnets.IPv4 = append(clusterSubnets.IPv4, subnetID)
+		}
+	}
+	if len(clusterSubnets.IPv4) == 0 && len(clusterSubnets.IPv6) == 0 {
+		return ClusterSubnets{}, fmt.Errorf("%s: no subnets", constants.ErrClusterSubnetInvalid)
+	}
+	return clusterSubnets, nil
+}
+
+func (s ClusterSubnets) IsDualStack() bool {
+	return len(s.IPv4) > 0 && len(s.IPv6) > 0
+}
+
+// VIPSubnetID returns the subnet of the load balancer VIPs. IPv4 is preferred
+// so that a floating IP can be attached to the VIP.
+func (s ClusterSubnets) VIPSubnetID() string {
+	if len(s.IPv4) > 0 {
+		return s.IPv4[0]
+	}
+	return s.IPv6[0]
+}
+
+// AdditionalVIPs returns the IPv6 VIP of the API and ingress load balancers
+// of dual-stack clusters.
+func (s ClusterSubnets) AdditionalVIPs() []request.AdditionalVIP {
+	if !s.IsDualStack() {
+		return nil
+	}
+	return []request.AdditionalVIP{{SubnetID: s.IPv6[0]}}
+}
+
+// FixedIps returns a random subnet of each IP version of the cluster, node
+// ports get an address of every version.
+func (s ClusterSubnets) FixedIps() []request.FixedIp {
+	fixedIps := []request.FixedIp{}
+	if len(s.IPv4) > 0 {
+		fixedIps = append(fixedIps, request.FixedIp{SubnetID: GetRandomStringFromArray(s.IPv4)})
+	}
+	if len(s.IPv6) > 0 {
+		fixedIps = append(fixedIps, request.FixedIp{SubnetID: GetRandomStringFromArray(s.IPv6)})
+	}
+	return fixedIps
+}
+
+const (
+	rke2DefaultPodCIDR     = "10.42.0.0/16"
+	rke2DefaultServiceCIDR = "10.43.0.0/16"
+)
+
+// ResolveRKE2NetworkSettings returns the pod and service CIDRs and the DNS
+// domain of the cluster. The CIDRs may hold one comma separated CIDR per IP
+// version of the cluster, without them the configured CIDRs of the IP
+// versions of the cluster are used. The effective CIDRs must not overlap each
+// other, the cluster subnets or the allowed CIDRs. CIDRs equal to the RKE2
+// defaults of IPv4 only clusters are validated but not rendered.
+func (s ClusterSubnets) ResolveRKE2NetworkSettings(podCIDR, serviceCIDR, clusterDomain string, allowedCIDRs []string) (RKE2NetworkSettings, error) {
+	networkConfig := config.GlobalConfig.GetClusterNetworkConfig()
+	podCIDRs, err := s.resolveCIDRs(podCIDR, networkConfig.PodCIDR, networkConfig.IPv6PodCIDR)
+	if err != nil {
+		return RKE2NetworkSettings{}, fmt.Errorf("%s: pod cidr %v", constants.ErrClusterNetworkInvalid, err)
+	}
+	serviceCIDRs, err := s.resolveCIDRs(serviceCIDR, networkConfig.ServiceCIDR, networkConfig.IPv6ServiceCIDR)
+	if err != nil {
+		return RKE2NetworkSettings{}, fmt.Errorf("%s: service cidr %v", constants.ErrClusterNetworkInvalid, err)
+	}
+
+	for _, cidr := range podCIDRs {
+		for _, serviceCIDR := range serviceCIDRs {
+			if cidrsOverlap(cidr, serviceCIDR) {
+				return RKE2NetworkSettings{}, fmt.Errorf("%s: pod cidr %s overlaps service cidr %s", constants.ErrClusterNetworkInvalid, cidr, serviceCIDR)
+			}
+		}
+	}
+	reservedCIDRs := append(append([]string{}, s.CIDRs...), allowedCIDRs...)
+	for _, cidr := range append(podCIDRs, serviceCIDRs...) {
+		for _, reservedCIDR := range reservedCIDRs {
+			if cidrsOverlap(cidr, reservedCIDR) {
+				return RKE2NetworkSettings{}, fmt.Errorf("%s: %s overlaps %s of the cluster subnets or allowed cidrs", constants.ErrClusterNetworkInvalid, cidr, reservedCIDR)
+			}
+		}
+	}
+
+	if clusterDomain != "" {
+		clusterDomain = strings.ToLower(strings.TrimSuffix(clusterDomain, "."))
+		if len(clusterDomain) > 253 {
+			return RKE2NetworkSettings{}, fmt.Errorf("%s: cluster domain %s is too long", constants.ErrClusterNetworkInvalid, clusterDomain)
+		}
+		for _, label := range strings.Split(clusterDomain, ".") {
+			if !dnsLabelPattern.MatchString(label) {
+				return RKE2NetworkSettings{}, fmt.Errorf("%s: cluster domain %s is not a valid dns name", constants.ErrClusterNetworkInvalid, clusterDomain)
+			}
+		}
+	}
+
+	return RKE2NetworkSettings{
+		ClusterCIDR:   s.renderedCIDRs(podCIDRs, rke2DefaultPodCIDR),
+		ServiceCIDR:   s.renderedCIDRs(serviceCIDRs, rke2DefaultServiceCIDR),
+		ClusterDomain: clusterDomain,
+	}, nil
+}
+
+// renderedCIDRs returns the CIDRs passed to the vke-agent, IPv4 only clusters
+// using the RKE2 default keep the agent invocation unchanged.
+func (s ClusterSubnets) renderedCIDRs(cidrs []string, rke2Default string) string {
+	if len(s.IPv6) == 0 && len(cidrs) == 1 && cidrs[0] == rke2Default {
+		return ""
+	}
+	return strings.Join(cidrs, ",")
+}
+
+// resolveCIDRs returns the CIDR of each IP version of the cluster, the
+// requested one or the configured default.
+func (s ClusterSubnets) resolveCIDRs(value, defaultIPv4, defaultIPv6 string) ([]string, error) {
+	requestedIPv4, requestedIPv6 := "", ""
+	if value != "" {
+		for _, cidr := range strings.Split(value, ",") {
+			ip, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+			if err != nil {
+				return nil, fmt.Errorf("%s is not a valid cidr", cidr)
+			}
+			if ip.To4() != nil {
+				if requestedIPv4 != "" || len(s.IPv4) == 0 {
+					return nil, fmt.Errorf("%s does not match the ip versions of the cluster subnets", value)
+				}
+				requestedIPv4 = ipNet.String()
+			} else {
+				if requestedIPv6 != "" || len(s.IPv6) == 0 {
+					return nil, fmt.Errorf("%s does not match the ip versions of the cluster subnets", value)
+				}
+				requestedIPv6 = ipNet.String()
+			}
+		}
+	}
+
+	cidrs := []string{}
+	if requestedIPv4 != "" {
+		cidrs = append(cidrs, requestedIPv4)
+	} else if len(s.IPv4) > 0 {
+		cidrs = append(cidrs, defaultIPv4)
+	}
+	if requestedIPv6 != "" {
+		cidrs = append(cidrs, requestedIPv6)
+	} else if len(s.IPv6) > 0 {
+		cidrs = append(cidrs, defaultIPv6)
+	}
+	return cidrs, nil
+}
+
+// cidrsOverlap reports whether two CIDRs share addresses. Default routes, as
+// used in allowed CIDRs to allow any address, overlap nothing.
+func cidrsOverlap(a, b string) bool {
+	_, aNet, err := net.ParseCIDR(a)
+	if err != nil {
+		return false
+	}
+	_, bNet, err := net.ParseCIDR(b)
+	if err != nil {
+		return false
+	}
+	if aOnes, _ := aNet.Mask.Size(); aOnes == 0 {
+		return false
+	}
+	if bOnes, _ := bNet.Mask.Size(); bOnes == 0 {
+		return false
+	}
+	return aNet.Contains(bNet.IP) || bNet.Contains(aNet.IP)
+}
+
+// PrimaryFixedIP returns the address of a port used for load balancer
+// members, IPv4 is preferred like for the VIPs.
+func PrimaryFixedIP(fixedIps []resource.FixedIp) resource.FixedIp {
+	for _, fixedIp := range fixedIps {
+		ip := net.ParseIP(fixedIp.IpAddress)
+		if ip != nil && ip.To4() != nil {
+			return fixedIp
+		}
+	}
+	if len(fixedIps) == 0 {
+		return resource.FixedIp{}
+	}
+	return fixedIps[0]
+}
+
+// SecurityGroupEthertype returns the ethertype of security group rules for
+// the CIDR.
+func SecurityGroupEthertype(cidr string) string {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err == nil && ip.To4() == nil {
+		return "IPv6"
+	}
+	return "IPv4"
+}
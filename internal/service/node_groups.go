@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -24,25 +26,357 @@ type INodeGroupsService interface {
 	UpdateNodeGroups(ctx context.Context, authToken, clusterID, nodeGroupID string, req request.UpdateNodeGroupRequest) (resource.UpdateNodeGroupResponse, error)
 	AddNode(ctx context.Context, authToken string, clusterUUID, nodeGroupUUID string) (resource.AddNodeResponse, error)
 	DeleteNode(ctx context.Context, authToken, clusterID, nodeGroupID, id string) (resource.DeleteNodeResponse, error)
+	DeleteUnhealthyNode(ctx context.Context, authToken, clusterID, nodeGroupID, id string) (resource.DeleteNodeResponse, error)
 	CreateNodeGroup(ctx context.Context, authToken, clusterID string, req request.CreateNodeGroupRequest) (resource.CreateNodeGroupResponse, error)
-	DeleteNodeGroup(ctx context.Context, authToken, clusterID, nodeGroupID string) error
+	DeleteNodeGroup(ctx context.Context, authToken, clusterID, nodeGroupID string, force bool) error
+	GetNodeGroupDeleteImpact(ctx context.Context, authToken, clusterID, nodeGroupID string) (resource.NodeGroupDeleteImpact, error)
+	CloneNodeGroup(ctx context.Context, authToken, clusterID, nodeGroupID string, req request.CloneNodeGroupRequest) (resource.CreateNodeGroupResponse, error)
+	CreateNodeGroupSchedule(ctx context.Context, authToken, clusterID, nodeGroupID string, req request.CreateNodeGroupScheduleRequest) (resource.NodeGroupSchedule, error)
+	GetNodeGroupSchedules(ctx context.Context, authToken, clusterID, nodeGroupID string) ([]resource.NodeGroupSchedule, error)
+	DeleteNodeGroupSchedule(ctx context.Context, authToken, clusterID, nodeGroupID, scheduleID string) error
+	IsScaling(nodeGroupID string) bool
 }
 
+// errNodeGroupScaling is returned by UpdateNodeGroups while a previous size
+// change of the node group is still running.
+var errNodeGroupScaling = errors.New("node group is already scaling")
+
 type nodeGroupsService struct {
 	repository      repository.IRepository
 	logger          *logrus.Logger
 	identityService IIdentityService
 	computeService  IComputeService
 	networkService  INetworkService
+	imageService    IImageService
+
+	kubernetesService IKubernetesService
+	ingressService    IIngressService
+
+	mu      sync.Mutex
+	scaling map[string]bool
+	// placementChecks holds the server groups with a running placement check,
+	// true when servers were added since the check started.
+	placementChecks map[string]bool
 }
 
-func NewNodeGroupsService(logger *logrus.Logger, repository repository.IRepository, i IIdentityService, c IComputeService, n INetworkService) INodeGroupsService {
+func NewNodeGroupsService(logger *logrus.Logger, repository repository.IRepository, i IIdentityService, c IComputeService, n INetworkService, im IImageService, k IKubernetesService, ig IIngressService) INodeGroupsService {
 	return &nodeGroupsService{
-		repository:      repository,
-		logger:          logger,
-		identityService: i,
-		computeService:  c,
-		networkService:  n,
+		repository:        repository,
+		logger:            logger,
+		identityService:   i,
+		computeService:    c,
+		networkService:    n,
+		imageService:      im,
+		kubernetesService: k,
+		ingressService:    ig,
+		scaling:           make(map[string]bool),
+		placementChecks:   make(map[string]bool),
+	}
+}
+
+// nodeGroupImageRef returns the image of the node group, node groups created
+// before per node group images fall back to the global IMAGE_REF.
+func nodeGroupImageRef(nodeGroup model.NodeGroups) string {
+	if nodeGroup.NodeImageRef != "" {
+		return nodeGroup.NodeImageRef
+	}
+	return config.GlobalConfig.GetImageRefConfig().ImageRef
+}
+
+const (
+	MaxNodeGroupDataVolumes = 8
+	MinDataVolumeSizeGB     = 1
+	MaxDataVolumeSizeGB     = 16384
+)
+
+func validateDataVolumes(dataVolumes []request.DataVolume) error {
+	if len(dataVolumes) > MaxNodeGroupDataVolumes {
+		return fmt.Errorf("too many data volumes, maximum is %d", MaxNodeGroupDataVolumes)
+	}
+	for i, dataVolume := range dataVolumes {
+		if dataVolume.SizeGB < MinDataVolumeSizeGB || dataVolume.SizeGB > MaxDataVolumeSizeGB {
+			return fmt.Errorf("invalid size for data volume %d, size must be between %d and %d GB", i+1, MinDataVolumeSizeGB, MaxDataVolumeSizeGB)
+		}
+		if len(dataVolume.VolumeType) > 255 {
+			return fmt.Errorf("invalid volume type for data volume %d", i+1)
+		}
+	}
+	return nil
+}
+
+// dataVolumeBlockDeviceMappings returns blank volumes which are created and
+// attached next to the boot volume when the server is created.
+func dataVolumeBlockDeviceMappings(dataVolumes []request.DataVolume) []request.BlockDeviceMappingV2 {
+	var mappings []request.BlockDeviceMappingV2
+	for _, dataVolume := range dataVolumes {
+		mappings = append(mappings, request.BlockDeviceMappingV2{
+			BootIndex:           -1,
+			SourceType:          "blank",
+			DestinationType:     "volume",
+			DeleteOnTermination: dataVolume.DeleteOnTermination,
+			VolumeSize:          dataVolume.SizeGB,
+			VolumeType:          dataVolume.VolumeType,
+		})
+	}
+	return mappings
+}
+
+func nodeGroupDataVolumes(nodeGroup model.NodeGroups) ([]request.DataVolume, error) {
+	dataVolumes := []request.DataVolume{}
+	if nodeGroup.NodeGroupDataVolumes == nil {
+		return dataVolumes, nil
+	}
+	err := json.Unmarshal(nodeGroup.NodeGroupDataVolumes, &dataVolumes)
+	return dataVolumes, err
+}
+
+func nodeGroupDataVolumesResponse(nodeGroup model.NodeGroups) []resource.DataVolume {
+	dataVolumes, _ := nodeGroupDataVolumes(nodeGroup)
+	resp := []resource.DataVolume{}
+	for _, dataVolume := range dataVolumes {
+		resp = append(resp, resource.DataVolume{
+			SizeGB:              dataVolume.SizeGB,
+			VolumeType:          dataVolume.VolumeType,
+			DeleteOnTermination: dataVolume.DeleteOnTermination,
+		})
+	}
+	return resp
+}
+
+const (
+	MaxNodeGroupAdditionalNetworks = 4
+	NetworkPortResourceType        = "network_port"
+)
+
+// validateAdditionalNetworks checks that the additional subnets and their
+// security groups exist and that they do not overlap with the cluster subnets.
+func (nodg *nodeGroupsService) validateAdditionalNetworks(ctx context.Context, authToken string, cluster *model.Cluster, additionalNetworks []request.AdditionalNetwork) error {
+	if len(additionalNetworks) > MaxNodeGroupAdditionalNetworks {
+		return fmt.Errorf("too many additional networks, maximum is %d", MaxNodeGroupAdditionalNetworks)
+	}
+
+	clusterSubnetIDs := ConvertDataJSONtoStringArray(cluster.ClusterSubnets)
+	seenSubnets := map[string]bool{}
+	for _, additionalNetwork := range additionalNetworks {
+		if additionalNetwork.SubnetID == "" {
+			return fmt.Errorf("subnet id of additional network is required")
+		}
+		for _, clusterSubnetID := range clusterSubnetIDs {
+			if clusterSubnetID == additionalNetwork.SubnetID {
+				return fmt.Errorf("subnet %s is already a cluster subnet", additionalNetwork.SubnetID)
+			}
+		}
+		if seenSubnets[additionalNetwork.SubnetID] {
+			return fmt.Errorf("subnet %s is declared more than once", additionalNetwork.SubnetID)
+		}
+		seenSubnets[additionalNetwork.SubnetID] = true
+
+		_, err := nodg.networkService.GetNetworkID(ctx, authToken, additionalNetwork.SubnetID)
+		if err != nil {
+			return fmt.Errorf("failed to get subnet %s: %v", additionalNetwork.SubnetID, err)
+		}
+		for _, securityGroupID := range additionalNetwork.SecurityGroupIDs {
+			_, err = nodg.networkService.GetSecurityGroupByID(ctx, authToken, securityGroupID)
+			if err != nil {
+				return fmt.Errorf("failed to get security group %s: %v", securityGroupID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// createAdditionalNetworkPorts creates a port on each additional network of
+// the node group and records it in the cluster resources.
+func (nodg *nodeGroupsService) createAdditionalNetworkPorts(ctx context.Context, authToken string, cluster *model.Cluster, portName string, additionalNetworks []request.AdditionalNetwork) ([]request.Networks, error) {
+	var networks []request.Networks
+	for _, additionalNetwork := range additionalNetworks {
+		networkIDResp, err := nodg.networkService.GetNetworkID(ctx, authToken, additionalNetwork.SubnetID)
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"subnetID": additionalNetwork.SubnetID,
+			}).WithError(err).Error("failed to get network id")
+			nodg.deleteNetworkPorts(ctx, authToken, cluster.ClusterUUID, networkPortIDs(networks))
+			return nil, err
+		}
+
+		securityGroupIDs := additionalNetwork.SecurityGroupIDs
+		if securityGroupIDs == nil {
+			securityGroupIDs = []string{}
+		}
+		portResp, err := nodg.networkService.CreateNetworkPort(ctx, authToken, request.CreateNetworkPortRequest{
+			Port: request.Port{
+				NetworkID:    networkIDResp.Subnet.NetworkID,
+				Name:         portName,
+				AdminStateUp: true,
+				FixedIps: []request.FixedIp{
+					{
+						SubnetID: additionalNetwork.SubnetID,
+					},
+				},
+				SecurityGroups: securityGroupIDs,
+			},
+		})
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"subnetID": additionalNetwork.SubnetID,
+			}).WithError(err).Error("failed to create additional network port")
+			nodg.deleteNetworkPorts(ctx, authToken, cluster.ClusterUUID, networkPortIDs(networks))
+			return nil, err
+		}
+
+		err = nodg.repository.Resources().CreateResource(ctx, &model.Resource{
+			ClusterUUID:  cluster.ClusterUUID,
+			ResourceType: NetworkPortResourceType,
+			ResourceUUID: portResp.Port.ID,
+		})
+		if err != nil {
+			nodg.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": cluster.ClusterUUID,
+			}).Error("failed to create resource")
+			nodg.deleteNetworkPorts(ctx, authToken, cluster.ClusterUUID, append(networkPortIDs(networks), portResp.Port.ID))
+			return nil, err
+		}
+
+		networks = append(networks, request.Networks{Port: portResp.Port.ID})
+	}
+	return networks, nil
+}
+
+func (nodg *nodeGroupsService) deleteNetworkPortResource(ctx context.Context, clusterUUID, portID string) {
+	err := nodg.repository.Resources().DeleteResource(ctx, clusterUUID, NetworkPortResourceType, portID)
+	if err != nil {
+		nodg.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+			"portID":      portID,
+		}).Error("failed to delete resource")
+	}
+}
+
+// deleteNetworkPorts removes the ports created for a server which could not
+// be created, together with their resources.
+func (nodg *nodeGroupsService) deleteNetworkPorts(ctx context.Context, authToken, clusterUUID string, portIDs []string) {
+	for _, portID := range portIDs {
+		err := nodg.networkService.DeleteNetworkPort(ctx, authToken, portID)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			nodg.logger.WithError(err).WithFields(logrus.Fields{
+				"clusterUUID": clusterUUID,
+				"portID":      portID,
+			}).Error("failed to delete network port")
+			continue
+		}
+		nodg.deleteNetworkPortResource(ctx, clusterUUID, portID)
+	}
+}
+
+func networkPortIDs(networks []request.Networks) []string {
+	portIDs := []string{}
+	for _, network := range networks {
+		portIDs = append(portIDs, network.Port)
+	}
+	return portIDs
+}
+
+func nodeGroupAdditionalNetworks(nodeGroup model.NodeGroups) ([]request.AdditionalNetwork, error) {
+	additionalNetworks := []request.AdditionalNetwork{}
+	if nodeGroup.NodeGroupNetworks == nil {
+		return additionalNetworks, nil
+	}
+	err := json.Unmarshal(nodeGroup.NodeGroupNetworks, &additionalNetworks)
+	return additionalNetworks, err
+}
+
+func nodeGroupAdditionalNetworksResponse(nodeGroup model.NodeGroups) []resource.AdditionalNetwork {
+	additionalNetworks, _ := nodeGroupAdditionalNetworks(nodeGroup)
+	resp := []resource.AdditionalNetwork{}
+	for _, additionalNetwork := range additionalNetworks {
+		resp = append(resp, resource.AdditionalNetwork{
+			SubnetID:         additionalNetwork.SubnetID,
+			SecurityGroupIDs: additionalNetwork.SecurityGroupIDs,
+		})
+	}
+	return resp
+}
+
+func nodeGroupAvailabilityZones(nodeGroup model.NodeGroups) ([]string, error) {
+	availabilityZones := []string{}
+	if nodeGroup.NodeGroupZones == nil {
+		return availabilityZones, nil
+	}
+	err := json.Unmarshal(nodeGroup.NodeGroupZones, &availabilityZones)
+	return availabilityZones, err
+}
+
+func nodeGroupAvailabilityZonesResponse(nodeGroup model.NodeGroups) []string {
+	availabilityZones, _ := nodeGroupAvailabilityZones(nodeGroup)
+	return availabilityZones
+}
+
+// nodeAvailabilityZone spreads the nodes of a node group over its availability
+// zones, node groups without zones use the default zone.
+func nodeAvailabilityZone(availabilityZones []string, index int) string {
+	if len(availabilityZones) == 0 {
+		return DefaultAvailabilityZone
+	}
+	return availabilityZones[index%len(availabilityZones)]
+}
+
+func validateAvailabilityZones(availabilityZones []string) error {
+	seen := map[string]bool{}
+	for _, availabilityZone := range availabilityZones {
+		if availabilityZone == "" {
+			return fmt.Errorf("availability zone can not be empty")
+		}
+		if seen[availabilityZone] {
+			return fmt.Errorf("availability zone %s is duplicated", availabilityZone)
+		}
+		seen[availabilityZone] = true
+	}
+	return nil
+}
+
+// getUserDataExtensions returns the user data snippets of the cluster followed
+// by the snippets of the node group.
+func getUserDataExtensions(userDataJSONs ...datatypes.JSON) ([]request.UserDataExtensions, error) {
+	var extensions []request.UserDataExtensions
+	for _, userDataJSON := range userDataJSONs {
+		if userDataJSON == nil {
+			continue
+		}
+		var extension request.UserDataExtensions
+		err := json.Unmarshal(userDataJSON, &extension)
+		if err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, extension)
+	}
+	return extensions, nil
+}
+
+// deleteDataVolumes removes the volumes which were attached with
+// delete_on_termination after their server is deleted. Nova deletes them
+// itself in most cases, volumes which are still detaching are retried. It runs
+// in the background so the retries do not hold up the node deletion.
+func (nodg *nodeGroupsService) deleteDataVolumes(ctx context.Context, authToken, serverID string, attachments []resource.VolumeAttachment) {
+	for _, attachment := range attachments {
+		if !attachment.DeleteOnTermination {
+			continue
+		}
+		var err error
+		for i := 0; i < 10; i++ {
+			err = nodg.computeService.DeleteVolume(ctx, authToken, attachment.VolumeID)
+			if err == nil || strings.Contains(err.Error(), "404") {
+				err = nil
+				break
+			}
+			time.Sleep(3 * time.Second)
+		}
+		if err != nil {
+			nodg.logger.WithError(err).WithFields(logrus.Fields{
+				"serverID": serverID,
+				"volumeID": attachment.VolumeID,
+			}).Error("failed to delete data volume")
+		}
 	}
 }
 
@@ -77,16 +411,22 @@ func (nodg *nodeGroupsService) GetNodeGroups(ctx context.Context, authToken, clu
 
 		var resp []resource.NodeGroup
 		resp = append(resp, resource.NodeGroup{
-			ClusterUUID:      nodeGroup.ClusterUUID,
-			NodeGroupUUID:    nodeGroup.NodeGroupUUID,
-			NodeGroupName:    nodeGroup.NodeGroupName,
-			NodeGroupMinSize: nodeGroup.NodeGroupMinSize,
-			NodeGroupMaxSize: nodeGroup.NodeGroupMaxSize,
-			NodeDiskSize:     nodeGroup.NodeDiskSize,
-			NodeFlavorUUID:   nodeGroup.NodeFlavorUUID,
-			NodeGroupsType:   nodeGroup.NodeGroupsType,
-			CurrentNodes:     count,
-			NodeGroupsStatus: nodeGroup.NodeGroupsStatus,
+			ClusterUUID:        nodeGroup.ClusterUUID,
+			NodeGroupUUID:      nodeGroup.NodeGroupUUID,
+			NodeGroupName:      nodeGroup.NodeGroupName,
+			NodeGroupMinSize:   nodeGroup.NodeGroupMinSize,
+			NodeGroupMaxSize:   nodeGroup.NodeGroupMaxSize,
+			NodeDiskSize:       nodeGroup.NodeDiskSize,
+			NodeFlavorUUID:     nodeGroup.NodeFlavorUUID,
+			NodeGroupsType:     nodeGroup.NodeGroupsType,
+			CurrentNodes:       count,
+			NodeGroupsStatus:   nodeGroup.NodeGroupsStatus,
+			AutoRepair:         nodeGroup.NodeGroupAutoRepair,
+			ServerGroupPolicy:  nodeGroup.ServerGroupPolicy,
+			ImageRef:           nodeGroupImageRef(*nodeGroup),
+			DataVolumes:        nodeGroupDataVolumesResponse(*nodeGroup),
+			AdditionalNetworks: nodeGroupAdditionalNetworksResponse(*nodeGroup),
+			AvailabilityZones:  nodeGroupAvailabilityZonesResponse(*nodeGroup),
 		})
 		return resp, nil
 	} else {
@@ -106,16 +446,22 @@ func (nodg *nodeGroupsService) GetNodeGroups(ctx context.Context, authToken, clu
 			}
 
 			resp = append(resp, resource.NodeGroup{
-				ClusterUUID:      nodeGroup.ClusterUUID,
-				NodeGroupUUID:    nodeGroup.NodeGroupUUID,
-				NodeGroupName:    nodeGroup.NodeGroupName,
-				NodeGroupMinSize: nodeGroup.NodeGroupMinSize,
-				NodeGroupMaxSize: nodeGroup.NodeGroupMaxSize,
-				NodeDiskSize:     nodeGroup.NodeDiskSize,
-				NodeFlavorUUID:   nodeGroup.NodeFlavorUUID,
-				NodeGroupsType:   nodeGroup.NodeGroupsType,
-				CurrentNodes:     count,
-				NodeGroupsStatus: nodeGroup.NodeGroupsStatus,
+				ClusterUUID:        nodeGroup.ClusterUUID,
+				NodeGroupUUID:      nodeGroup.NodeGroupUUID,
+				NodeGroupName:      nodeGroup.NodeGroupName,
+				NodeGroupMinSize:   nodeGroup.NodeGroupMinSize,
+				NodeGroupMaxSize:   nodeGroup.NodeGroupMaxSize,
+				NodeDiskSize:       nodeGroup.NodeDiskSize,
+				NodeFlavorUUID:     nodeGroup.NodeFlavorUUID,
+				NodeGroupsType:     nodeGroup.NodeGroupsType,
+				CurrentNodes:       count,
+				NodeGroupsStatus:   nodeGroup.NodeGroupsStatus,
+				AutoRepair:         nodeGroup.NodeGroupAutoRepair,
+				ServerGroupPolicy:  nodeGroup.ServerGroupPolicy,
+				ImageRef:           nodeGroupImageRef(nodeGroup),
+				DataVolumes:        nodeGroupDataVolumesResponse(nodeGroup),
+				AdditionalNetworks: nodeGroupAdditionalNetworksResponse(nodeGroup),
+				AvailabilityZones:  nodeGroupAvailabilityZonesResponse(nodeGroup),
 			})
 		}
 		return resp, nil
@@ -135,16 +481,22 @@ func (nodg *nodeGroupsService) GetNodeGroupsByClusterUUID(ctx context.Context, c
 
 	for _, nodeGroup := range nodeGroups {
 		resp = append(resp, resource.NodeGroup{
-			ClusterUUID:      nodeGroup.ClusterUUID,
-			NodeGroupUUID:    nodeGroup.NodeGroupUUID,
-			NodeGroupName:    nodeGroup.NodeGroupName,
-			NodeGroupMinSize: nodeGroup.NodeGroupMinSize,
-			NodeGroupMaxSize: nodeGroup.NodeGroupMaxSize,
-			NodeDiskSize:     nodeGroup.NodeDiskSize,
-			NodeFlavorUUID:   nodeGroup.NodeFlavorUUID,
-			NodeGroupsType:   nodeGroup.NodeGroupsType,
-			CurrentNodes:     0, //ToDo: Keep current node count in db
-			NodeGroupsStatus: nodeGroup.NodeGroupsStatus,
+			ClusterUUID:        nodeGroup.ClusterUUID,
+			NodeGroupUUID:      nodeGroup.NodeGroupUUID,
+			NodeGroupName:      nodeGroup.NodeGroupName,
+			NodeGroupMinSize:   nodeGroup.NodeGroupMinSize,
+			NodeGroupMaxSize:   nodeGroup.NodeGroupMaxSize,
+			NodeDiskSize:       nodeGroup.NodeDiskSize,
+			NodeFlavorUUID:     nodeGroup.NodeFlavorUUID,
+			NodeGroupsType:     nodeGroup.NodeGroupsType,
+			CurrentNodes:       0, //ToDo: Keep current node count in db
+			NodeGroupsStatus:   nodeGroup.NodeGroupsStatus,
+			AutoRepair:         nodeGroup.NodeGroupAutoRepair,
+			ServerGroupPolicy:  nodeGroup.ServerGroupPolicy,
+			ImageRef:           nodeGroupImageRef(nodeGroup),
+			DataVolumes:        nodeGroupDataVolumesResponse(nodeGroup),
+			AdditionalNetworks: nodeGroupAdditionalNetworksResponse(nodeGroup),
+			AvailabilityZones:  nodeGroupAvailabilityZonesResponse(nodeGroup),
 		})
 
 	}
@@ -153,6 +505,12 @@ func (nodg *nodeGroupsService) GetNodeGroupsByClusterUUID(ctx context.Context, c
 }
 
 func (nodg *nodeGroupsService) AddNode(ctx context.Context, authToken string, clusterUUID, nodeGroupUUD string) (resource.AddNodeResponse, error) {
+	return nodg.addNode(ctx, authToken, clusterUUID, nodeGroupUUD, true)
+}
+
+// addNode adds a server to the node group. Callers adding several servers
+// skip the placement check and run it once after the last one.
+func (nodg *nodeGroupsService) addNode(ctx context.Context, authToken string, clusterUUID, nodeGroupUUD string, checkPlacement bool) (resource.AddNodeResponse, error) {
 	token := strings.Clone(authToken)
 	if token == "" {
 		nodg.logger.WithFields(logrus.Fields{
@@ -227,25 +585,9 @@ func (nodg *nodeGroupsService) AddNode(ctx context.Context, authToken string, cl
 		return resource.AddNodeResponse{}, err
 	}
 
-	randSubnetId := GetRandomStringFromArray(subnetIDs)
-
-	createPortRequest := request.CreateNetworkPortRequest{
-		Port: request.Port{
-			Name:         fmt.Sprintf("%s-%s", cluster.ClusterName, nodeGroup.NodeGroupName),
-			NetworkID:    networkIDResp.Subnet.NetworkID,
-			AdminStateUp: true,
-			FixedIps: []request.FixedIp{
-				{
-					SubnetID: randSubnetId,
-				},
-			},
-			SecurityGroups: []string{cluster.ClusterSharedSecurityGroup, nodeGroup.NodeGroupSecurityGroup},
-		},
-	}
-
-	portResp, err := nodg.networkService.CreateNetworkPort(ctx, token, createPortRequest)
+	clusterSubnets, err := nodg.networkService.GetClusterSubnets(ctx, token, subnetIDs)
 	if err != nil {
-		nodg.logger.WithError(err).Error("failed to create network port")
+		nodg.logger.WithError(err).Error("failed to get cluster subnets")
 		return resource.AddNodeResponse{}, err
 	}
 
@@ -267,6 +609,12 @@ func (nodg *nodeGroupsService) AddNode(ctx context.Context, authToken string, cl
 		}
 	}
 
+	userDataExtensions, err := getUserDataExtensions(cluster.ClusterUserData, nodeGroup.NodeGroupUserData)
+	if err != nil {
+		nodg.logger.WithError(err).Error("failed to unmarshal user data extensions")
+		return resource.AddNodeResponse{}, err
+	}
+
 	rke2InitScript, err := GenerateUserDataFromTemplate("false",
 		WorkerServerType,
 		cluster.ClusterRegisterToken,
@@ -287,6 +635,9 @@ func (nodg *nodeGroupsService) AddNode(ctx context.Context, authToken string, cl
 		"",
 		"",
 		config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+		nil,
+		clusterRKE2NetworkSettings(cluster),
+		userDataExtensions,
 	)
 	if err != nil {
 		nodg.logger.WithError(err).Error("failed to generate user data from template")
@@ -304,20 +655,63 @@ func (nodg *nodeGroupsService) AddNode(ctx context.Context, authToken string, cl
 		return resource.AddNodeResponse{}, err
 	}
 
+	dataVolumes, err := nodeGroupDataVolumes(*nodeGroup)
+	if err != nil {
+		nodg.logger.WithError(err).Error("failed to unmarshal node group data volumes")
+		return resource.AddNodeResponse{}, err
+	}
+
+	additionalNetworks, err := nodeGroupAdditionalNetworks(*nodeGroup)
+	if err != nil {
+		nodg.logger.WithError(err).Error("failed to unmarshal node group additional networks")
+		return resource.AddNodeResponse{}, err
+	}
+
+	availabilityZones, err := nodeGroupAvailabilityZones(*nodeGroup)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+		}).WithError(err).Error("failed to unmarshal node group availability zones")
+		return resource.AddNodeResponse{}, err
+	}
+
+	createPortRequest := request.CreateNetworkPortRequest{
+		Port: request.Port{
+			Name:           fmt.Sprintf("%s-%s", cluster.ClusterName, nodeGroup.NodeGroupName),
+			NetworkID:      networkIDResp.Subnet.NetworkID,
+			AdminStateUp:   true,
+			FixedIps:       clusterSubnets.FixedIps(),
+			SecurityGroups: []string{cluster.ClusterSharedSecurityGroup, nodeGroup.NodeGroupSecurityGroup},
+		},
+	}
+
+	portResp, err := nodg.networkService.CreateNetworkPort(ctx, token, createPortRequest)
+	if err != nil {
+		nodg.logger.WithError(err).Error("failed to create network port")
+		return resource.AddNodeResponse{}, err
+	}
+
+	additionalPorts, err := nodg.createAdditionalNetworkPorts(ctx, token, cluster, fmt.Sprintf("%s-%s", cluster.ClusterName, nodeGroup.NodeGroupName), additionalNetworks)
+	if err != nil {
+		nodg.deleteNetworkPorts(ctx, token, cluster.ClusterUUID, []string{portResp.Port.ID})
+		return resource.AddNodeResponse{}, err
+	}
+
+	imageRef := nodeGroupImageRef(*nodeGroup)
 	createServerRequest := request.CreateComputeRequest{
 		Server: request.Server{
 			Name:             nodeGroup.NodeGroupName + "-" + uuid.New().String()[:8],
-			ImageRef:         config.GlobalConfig.GetImageRefConfig().ImageRef,
+			ImageRef:         imageRef,
 			FlavorRef:        nodeGroup.NodeFlavorUUID,
 			KeyName:          cluster.ClusterNodeKeypairName,
-			AvailabilityZone: "nova",
+			AvailabilityZone: nodeAvailabilityZone(availabilityZones, currentCount),
 			BlockDeviceMappingV2: []request.BlockDeviceMappingV2{
 				{
 					BootIndex:           0,
 					DestinationType:     "volume",
 					DeleteOnTermination: true,
 					SourceType:          "image",
-					UUID:                config.GlobalConfig.GetImageRefConfig().ImageRef,
+					UUID:                imageRef,
 					VolumeSize:          nodeGroup.NodeDiskSize,
 				},
 			},
@@ -334,13 +728,30 @@ func (nodg *nodeGroupsService) AddNode(ctx context.Context, authToken string, cl
 			Group: nodeGroup.NodeGroupUUID,
 		},
 	}
+	createServerRequest.Server.BlockDeviceMappingV2 = append(createServerRequest.Server.BlockDeviceMappingV2, dataVolumeBlockDeviceMappings(dataVolumes)...)
+	createServerRequest.Server.Networks = append(createServerRequest.Server.Networks, additionalPorts...)
 
 	serverResp, err := nodg.computeService.CreateCompute(ctx, token, createServerRequest)
 	if err != nil {
 		nodg.logger.WithError(err).Error("failed to create compute")
+		nodg.deleteNetworkPorts(ctx, token, cluster.ClusterUUID, networkPortIDs(createServerRequest.Server.Networks))
 		return resource.AddNodeResponse{}, err
 	}
 
+	if checkPlacement {
+		go nodg.checkServerGroupPlacement(context.Background(), token, cluster, nodeGroup.NodeGroupName, nodeGroup.NodeGroupUUID, nodeGroup.ServerGroupPolicy)
+	}
+
+	if len(portResp.Port.FixedIps) > 0 {
+		memberFixedIP := PrimaryFixedIP(portResp.Port.FixedIps)
+		err = nodg.ingressService.AddNodeMember(ctx, token, cluster.ClusterUUID, nodeGroup.NodeGroupUUID, createServerRequest.Server.Name, memberFixedIP.SubnetID, memberFixedIP.IpAddress)
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"instanceUUID": serverResp.Server.ID,
+			}).WithError(err).Warn("failed to add node to ingress load balancer")
+		}
+	}
+
 	err = nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
 		ClusterUUID: cluster.ClusterUUID,
 		ProjectUUID: cluster.ClusterProjectUUID,
@@ -370,6 +781,16 @@ func (nodg *nodeGroupsService) AddNode(ctx context.Context, authToken string, cl
 }
 
 func (nodg *nodeGroupsService) DeleteNode(ctx context.Context, authToken string, clusterUUID string, nodeGroupID string, id string) (resource.DeleteNodeResponse, error) {
+	return nodg.deleteNode(ctx, authToken, clusterUUID, nodeGroupID, id, true)
+}
+
+// DeleteUnhealthyNode deletes a node without the min size check, auto repair
+// uses it to free a slot in a full node group before adding the replacement.
+func (nodg *nodeGroupsService) DeleteUnhealthyNode(ctx context.Context, authToken string, clusterUUID string, nodeGroupID string, id string) (resource.DeleteNodeResponse, error) {
+	return nodg.deleteNode(ctx, authToken, clusterUUID, nodeGroupID, id, false)
+}
+
+func (nodg *nodeGroupsService) deleteNode(ctx context.Context, authToken string, clusterUUID string, nodeGroupID string, id string, checkMinSize bool) (resource.DeleteNodeResponse, error) {
 	token := strings.Clone(authToken)
 	if token == "" {
 		nodg.logger.WithFields(logrus.Fields{
@@ -419,7 +840,7 @@ func (nodg *nodeGroupsService) DeleteNode(ctx context.Context, authToken string,
 		}).WithError(err).Error("failed to get count of server from server group")
 		return resource.DeleteNodeResponse{}, err
 	}
-	if computeCount <= ng.NodeGroupMinSize {
+	if checkMinSize && computeCount <= ng.NodeGroupMinSize {
 		nodg.logger.WithFields(logrus.Fields{
 			"nodeGroupUUID": ng.NodeGroupUUID,
 		}).WithError(err).Error("failed to delete node, node group min size reached")
@@ -432,6 +853,18 @@ func (nodg *nodeGroupsService) DeleteNode(ctx context.Context, authToken string,
 		}).WithError(err).Error("failed to get compute network ports")
 		return resource.DeleteNodeResponse{}, err
 	}
+	volumeAttachments, err := nodg.computeService.GetServerVolumeAttachments(ctx, token, id)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"instanceUUID": id,
+		}).WithError(err).Warn("failed to get compute volume attachments")
+	}
+	err = nodg.ingressService.RemoveNodeMembers(ctx, token, cluster.ClusterUUID, ng.NodeGroupUUID, id)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"instanceUUID": id,
+		}).WithError(err).Warn("failed to remove node from ingress load balancer")
+	}
 	err = nodg.computeService.DeleteCompute(ctx, token, id)
 	if err != nil {
 		nodg.logger.WithFields(logrus.Fields{
@@ -439,6 +872,7 @@ func (nodg *nodeGroupsService) DeleteNode(ctx context.Context, authToken string,
 		}).WithError(err).Error("failed to delete compute")
 		return resource.DeleteNodeResponse{}, err
 	}
+	go nodg.deleteDataVolumes(context.Background(), token, id, volumeAttachments)
 	for _, portID := range getPortIDs.Ports {
 		err = nodg.networkService.DeleteNetworkPort(ctx, token, portID)
 		if err != nil {
@@ -447,6 +881,7 @@ func (nodg *nodeGroupsService) DeleteNode(ctx context.Context, authToken string,
 			}).WithError(err).Error("failed to delete network port")
 			return resource.DeleteNodeResponse{}, err
 		}
+		nodg.deleteNetworkPortResource(ctx, cluster.ClusterUUID, portID)
 	}
 
 	err = nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
@@ -491,85 +926,363 @@ func (nodg *nodeGroupsService) UpdateNodeGroups(ctx context.Context, authToken,
 		return resource.UpdateNodeGroupResponse{}, err
 	}
 
-	err = nodg.repository.NodeGroups().UpdateNodeGroups(ctx, &model.NodeGroups{
-		NodeGroupUUID:    nodeGroupID,
-		NodeGroupMinSize: int(*req.MinNodes),
-		NodeGroupMaxSize: int(*req.MaxNodes),
-	})
-	if err != nil {
-		nodg.logger.WithFields(logrus.Fields{
-			"nodeGroupID": nodeGroupID,
-		}).WithError(err).Error("failed to update node group")
-		return resource.UpdateNodeGroupResponse{}, err
-	}
-	response := resource.UpdateNodeGroupResponse{
-		ClusterID:   clusterID,
-		NodeGroupID: nodeGroupID,
-		MinSize:     int(*req.MinNodes),
-		MaxSize:     int(*req.MaxNodes),
-		Status:      getCurrentStateOfNodeGroup.NodeGroupsStatus,
+	resize := req.MinNodes != nil || req.MaxNodes != nil || req.DesiredNodes != nil
+	if resize && getCurrentStateOfNodeGroup.NodeGroupsType != NodeGroupWorkerType {
+		return resource.UpdateNodeGroupResponse{}, fmt.Errorf("node group size can only be changed for worker node groups")
 	}
-	return response, nil
-}
-
-func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, clusterID string, req request.CreateNodeGroupRequest) (resource.CreateNodeGroupResponse, error) {
-	token := strings.Clone(authToken)
 
-	var nodeGroupTaintsJSON datatypes.JSON
+	minSize := getCurrentStateOfNodeGroup.NodeGroupMinSize
+	maxSize := getCurrentStateOfNodeGroup.NodeGroupMaxSize
+	if req.MinNodes != nil {
+		minSize = int(*req.MinNodes)
+	}
+	if req.MaxNodes != nil {
+		maxSize = int(*req.MaxNodes)
+	}
+	if maxSize < 1 {
+		return resource.UpdateNodeGroupResponse{}, fmt.Errorf("max nodes must be at least 1")
+	}
+	if minSize > maxSize {
+		return resource.UpdateNodeGroupResponse{}, fmt.Errorf("min nodes must be less than or equal to max nodes")
+	}
 
-	if len(req.NodeGroupName) > 20 {
-		return resource.CreateNodeGroupResponse{}, fmt.Errorf("node group name is too long")
+	scaleInPolicy := req.ScaleInPolicy
+	if scaleInPolicy == "" {
+		scaleInPolicy = NodeGroupScaleInReject
 	}
-	cluster, err := nodg.repository.Cluster().GetClusterByUUID(ctx, clusterID)
-	if err != nil {
-		nodg.logger.WithFields(logrus.Fields{
-			"clusterID": clusterID,
-		}).WithError(err).Error("failed to get cluster by uuid")
-		return resource.CreateNodeGroupResponse{}, err
+	if scaleInPolicy != NodeGroupScaleInReject && scaleInPolicy != NodeGroupScaleInDrain {
+		return resource.UpdateNodeGroupResponse{}, fmt.Errorf("scale in policy must be %s or %s", NodeGroupScaleInReject, NodeGroupScaleInDrain)
 	}
 
-	if cluster == nil {
+	members, err := nodg.computeService.GetServerGroupMemberList(ctx, token, nodeGroupID)
+	if err != nil {
 		nodg.logger.WithFields(logrus.Fields{
-			"clusterID": clusterID,
-		}).Error("failed to get cluster")
-		return resource.CreateNodeGroupResponse{}, fmt.Errorf("failed to get cluster")
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to get server group member list")
+		return resource.UpdateNodeGroupResponse{}, err
 	}
+	currentSize := len(members.Members)
 
-	if cluster.ClusterProjectUUID == "" {
-		nodg.logger.WithFields(logrus.Fields{
-			"clusterID": clusterID,
-		}).Error("failed to get cluster")
-		return resource.CreateNodeGroupResponse{}, fmt.Errorf("failed to get cluster")
+	desiredSize := currentSize
+	switch {
+	case req.DesiredNodes != nil:
+		desiredSize = int(*req.DesiredNodes)
+		if desiredSize < minSize || desiredSize > maxSize {
+			return resource.UpdateNodeGroupResponse{}, fmt.Errorf("desired nodes must be between min and max nodes")
+		}
+	case currentSize < minSize:
+		desiredSize = minSize
+	case currentSize > maxSize:
+		desiredSize = maxSize
+	}
+	// Nodes are only removed after their pods were evicted, the reject policy
+	// refuses any update that would shrink the node group.
+	if desiredSize < currentSize && scaleInPolicy != NodeGroupScaleInDrain {
+		return resource.UpdateNodeGroupResponse{}, fmt.Errorf("node group has %d nodes which is more than the desired %d nodes, use scale in policy %s to remove them", currentSize, desiredSize, NodeGroupScaleInDrain)
 	}
 
-	err = nodg.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
-	if err != nil {
-		nodg.logger.WithFields(logrus.Fields{
-			"clusterProjectUUID": cluster.ClusterProjectUUID,
-		}).WithError(err).Error("failed to check auth token")
-		return resource.CreateNodeGroupResponse{}, err
+	if desiredSize != currentSize && !nodg.startScaling(nodeGroupID) {
+		return resource.UpdateNodeGroupResponse{}, errNodeGroupScaling
 	}
 
-	if req.NodeGroupTaints != nil {
-		for _, taint := range req.NodeGroupTaints {
-			if !strings.Contains(taint, "=") {
-				return resource.CreateNodeGroupResponse{}, fmt.Errorf("invalid taint format")
-			}
+	if req.MinNodes != nil || req.MaxNodes != nil {
+		err = nodg.repository.NodeGroups().UpdateNodeGroupSize(ctx, nodeGroupID, minSize, maxSize)
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"nodeGroupID": nodeGroupID,
+			}).WithError(err).Error("failed to update node group")
+			nodg.finishScaling(nodeGroupID, desiredSize != currentSize)
+			return resource.UpdateNodeGroupResponse{}, err
 		}
+	}
 
-		nodeGroupTaintsJSON, err = json.Marshal(req.NodeGroupTaints)
+	autoRepair := getCurrentStateOfNodeGroup.NodeGroupAutoRepair
+	if req.AutoRepair != nil {
+		if *req.AutoRepair && getCurrentStateOfNodeGroup.NodeGroupsType != NodeGroupWorkerType {
+			nodg.finishScaling(nodeGroupID, desiredSize != currentSize)
+			return resource.UpdateNodeGroupResponse{}, fmt.Errorf("auto repair is only supported for worker node groups")
+		}
+		autoRepair = *req.AutoRepair
+		err = nodg.repository.NodeGroups().UpdateNodeGroupAutoRepair(ctx, nodeGroupID, autoRepair)
 		if err != nil {
 			nodg.logger.WithFields(logrus.Fields{
-				"nodeGroupTaints": req.NodeGroupTaints,
-			}).WithError(err).Error("failed to marshal node group taints")
-			return resource.CreateNodeGroupResponse{}, err
+				"nodeGroupID": nodeGroupID,
+			}).WithError(err).Error("failed to update node group auto repair")
+			nodg.finishScaling(nodeGroupID, desiredSize != currentSize)
+			return resource.UpdateNodeGroupResponse{}, err
 		}
 	}
 
-	if len(req.NodeGroupLabels) == 0 {
-		req.NodeGroupLabels = []string{"nodegroup-name=" + req.NodeGroupName}
+	if desiredSize != currentSize {
+		nodg.createNodeGroupAuditLog(ctx, clusterProjectUUID, fmt.Sprintf("Node group %s scaling from %d to %d nodes started", getCurrentStateOfNodeGroup.NodeGroupName, currentSize, desiredSize))
+		go nodg.scaleNodeGroupInBackground(context.Background(), token, clusterProjectUUID, getCurrentStateOfNodeGroup, desiredSize, scaleInPolicy == NodeGroupScaleInDrain)
 	}
-	nodeGroupLabelsJSON, err := json.Marshal(req.NodeGroupLabels)
+
+	response := resource.UpdateNodeGroupResponse{
+		ClusterID:   clusterID,
+		NodeGroupID: nodeGroupID,
+		MinSize:     minSize,
+		MaxSize:     maxSize,
+		DesiredSize: desiredSize,
+		CurrentSize: currentSize,
+		Status:      getCurrentStateOfNodeGroup.NodeGroupsStatus,
+		AutoRepair:  autoRepair,
+	}
+	return response, nil
+}
+
+// checkServerGroupPlacement waits until the servers of the node group are
+// scheduled. The servers exist at this point, so a placement violation is
+// recorded as a cluster error and in the audit log instead of failing the
+// request. A server group has one check at a time, servers added while it
+// runs are covered by running it once more.
+func (nodg *nodeGroupsService) checkServerGroupPlacement(ctx context.Context, token string, cluster *model.Cluster, nodeGroupName, serverGroupID, policy string) {
+	if policy != ServerGroupPolicyAntiAffinity || !nodg.startPlacementCheck(serverGroupID) {
+		return
+	}
+
+	for {
+		err := nodg.computeService.CheckServerGroupPlacement(ctx, token, serverGroupID, policy)
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"clusterUUID":   cluster.ClusterUUID,
+				"serverGroupID": serverGroupID,
+			}).WithError(err).Error("failed to place node group servers")
+			baseMessage := constants.ErrComputeAntiAffinityCapacity
+			if errors.Is(err, errServerGroupPlacementTimeout) {
+				baseMessage = constants.ErrComputePlacementTimeout
+			}
+			nodg.logNodeGroupError(ctx, cluster.ClusterUUID, baseMessage, fmt.Sprintf("node group %s: %v", nodeGroupName, err))
+			nodg.createNodeGroupAuditLog(ctx, cluster, fmt.Sprintf("Node group %s placement failed: %v", nodeGroupName, err))
+		}
+		if !nodg.finishPlacementCheck(serverGroupID) {
+			return
+		}
+	}
+}
+
+// startPlacementCheck marks the placement check of the server group as
+// running, it returns false when one is running already.
+func (nodg *nodeGroupsService) startPlacementCheck(serverGroupID string) bool {
+	nodg.mu.Lock()
+	defer nodg.mu.Unlock()
+	if _, running := nodg.placementChecks[serverGroupID]; running {
+		nodg.placementChecks[serverGroupID] = true
+		return false
+	}
+	nodg.placementChecks[serverGroupID] = false
+	return true
+}
+
+// finishPlacementCheck ends the placement check of the server group, it
+// returns true when servers were added meanwhile and the check must run again.
+func (nodg *nodeGroupsService) finishPlacementCheck(serverGroupID string) bool {
+	nodg.mu.Lock()
+	defer nodg.mu.Unlock()
+	if nodg.placementChecks[serverGroupID] {
+		nodg.placementChecks[serverGroupID] = false
+		return true
+	}
+	delete(nodg.placementChecks, serverGroupID)
+	return false
+}
+
+// logNodeGroupError records the error of the node group in the cluster
+// errors, which are returned by the cluster errors endpoint.
+func (nodg *nodeGroupsService) logNodeGroupError(ctx context.Context, clusterUUID, baseMessage, details string) {
+	err := nodg.repository.Error().CreateError(ctx, &model.Error{
+		ClusterUUID:  clusterUUID,
+		ErrorMessage: constants.GetDetailedErrorMessage(baseMessage, "node_group_placement", clusterUUID, details),
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		nodg.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+		}).Error("failed to save cluster error")
+	}
+}
+
+// startScaling marks the node group as scaling, it returns false when a
+// scaling of the node group is already running.
+func (nodg *nodeGroupsService) startScaling(nodeGroupID string) bool {
+	nodg.mu.Lock()
+	defer nodg.mu.Unlock()
+	if nodg.scaling[nodeGroupID] {
+		return false
+	}
+	nodg.scaling[nodeGroupID] = true
+	return true
+}
+
+// IsScaling reports whether a size change of the node group is running.
+func (nodg *nodeGroupsService) IsScaling(nodeGroupID string) bool {
+	nodg.mu.Lock()
+	defer nodg.mu.Unlock()
+	return nodg.scaling[nodeGroupID]
+}
+
+func (nodg *nodeGroupsService) finishScaling(nodeGroupID string, started bool) {
+	if !started {
+		return
+	}
+	nodg.mu.Lock()
+	defer nodg.mu.Unlock()
+	delete(nodg.scaling, nodeGroupID)
+}
+
+// scaleNodeGroupInBackground scales the node group outside of the request,
+// draining and adding nodes takes minutes. The result is recorded in the
+// audit log of the cluster, the placement of added nodes is checked once.
+func (nodg *nodeGroupsService) scaleNodeGroupInBackground(ctx context.Context, token string, cluster *model.Cluster, nodeGroup *model.NodeGroups, desired int, drain bool) {
+	nodeGroupName := nodeGroup.NodeGroupName
+	nodeGroupID := nodeGroup.NodeGroupUUID
+	defer nodg.finishScaling(nodeGroupID, true)
+
+	current, added, err := nodg.scaleNodeGroup(ctx, token, cluster.ClusterUUID, nodeGroupID, desired, drain)
+	if added {
+		go nodg.checkServerGroupPlacement(ctx, token, cluster, nodeGroupName, nodeGroupID, nodeGroup.ServerGroupPolicy)
+	}
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID":  nodeGroupID,
+			"desiredNodes": desired,
+			"currentNodes": current,
+		}).WithError(err).Error("failed to scale node group")
+		nodg.createNodeGroupAuditLog(ctx, cluster, fmt.Sprintf("Node group %s scaling failed at %d of %d nodes: %v", nodeGroupName, current, desired, err))
+		return
+	}
+	nodg.createNodeGroupAuditLog(ctx, cluster, fmt.Sprintf("Node group %s scaled to %d nodes", nodeGroupName, current))
+}
+
+func (nodg *nodeGroupsService) createNodeGroupAuditLog(ctx context.Context, cluster *model.Cluster, event string) {
+	err := nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
+		ClusterUUID: cluster.ClusterUUID,
+		ProjectUUID: cluster.ClusterProjectUUID,
+		Event:       event,
+		CreateDate:  time.Now(),
+	})
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to create audit log")
+	}
+}
+
+// scaleNodeGroup adds or deletes nodes until the server group of the node
+// group has the desired number of members and returns the resulting count and
+// whether nodes were added. With drain the kubernetes node is drained before
+// its server is deleted.
+func (nodg *nodeGroupsService) scaleNodeGroup(ctx context.Context, token, clusterID, nodeGroupID string, desired int, drain bool) (int, bool, error) {
+	members, err := nodg.computeService.GetServerGroupMemberList(ctx, token, nodeGroupID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	current := len(members.Members)
+	added := false
+	for ; current < desired; current++ {
+		_, err = nodg.addNode(ctx, token, clusterID, nodeGroupID, false)
+		if err != nil {
+			return current, added, err
+		}
+		added = true
+	}
+
+	for ; current > desired; current-- {
+		serverID := members.Members[current-1]
+		if drain {
+			err = nodg.drainServer(ctx, token, clusterID, serverID)
+			if err != nil {
+				return current, false, err
+			}
+		}
+		_, err = nodg.DeleteNode(ctx, token, clusterID, nodeGroupID, serverID)
+		if err != nil {
+			return current, false, err
+		}
+	}
+
+	return current, added, nil
+}
+
+func (nodg *nodeGroupsService) drainServer(ctx context.Context, token, clusterUUID, serverID string) error {
+	server, err := nodg.computeService.GetInstancesDetail(ctx, token, serverID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"instanceUUID": serverID,
+		}).WithError(err).Error("failed to get instance detail")
+		return err
+	}
+
+	err = nodg.kubernetesService.DrainNode(ctx, clusterUUID, strings.ToLower(server.OpenstackServers.Name))
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterUUID": clusterUUID,
+			"nodeName":    server.OpenstackServers.Name,
+		}).WithError(err).Error("failed to drain kubernetes node")
+		return err
+	}
+	return nil
+}
+
+func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, clusterID string, req request.CreateNodeGroupRequest) (resource.CreateNodeGroupResponse, error) {
+	token := strings.Clone(authToken)
+
+	var nodeGroupTaintsJSON datatypes.JSON
+
+	if len(req.NodeGroupName) > 20 {
+		return resource.CreateNodeGroupResponse{}, fmt.Errorf("node group name is too long")
+	}
+	cluster, err := nodg.repository.Cluster().GetClusterByUUID(ctx, clusterID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterID": clusterID,
+		}).WithError(err).Error("failed to get cluster by uuid")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	if cluster == nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterID": clusterID,
+		}).Error("failed to get cluster")
+		return resource.CreateNodeGroupResponse{}, fmt.Errorf("failed to get cluster")
+	}
+
+	if cluster.ClusterProjectUUID == "" {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterID": clusterID,
+		}).Error("failed to get cluster")
+		return resource.CreateNodeGroupResponse{}, fmt.Errorf("failed to get cluster")
+	}
+
+	err = nodg.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterProjectUUID": cluster.ClusterProjectUUID,
+		}).WithError(err).Error("failed to check auth token")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	if req.NodeGroupTaints != nil {
+		for _, taint := range req.NodeGroupTaints {
+			if !strings.Contains(taint, "=") {
+				return resource.CreateNodeGroupResponse{}, fmt.Errorf("invalid taint format")
+			}
+		}
+
+		nodeGroupTaintsJSON, err = json.Marshal(req.NodeGroupTaints)
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"nodeGroupTaints": req.NodeGroupTaints,
+			}).WithError(err).Error("failed to marshal node group taints")
+			return resource.CreateNodeGroupResponse{}, err
+		}
+	}
+
+	if len(req.NodeGroupLabels) == 0 {
+		req.NodeGroupLabels = []string{"nodegroup-name=" + req.NodeGroupName}
+	}
+	nodeGroupLabelsJSON, err := json.Marshal(req.NodeGroupLabels)
 	if err != nil {
 		nodg.logger.WithFields(logrus.Fields{
 			"nodeGroupLabels": req.NodeGroupLabels,
@@ -577,10 +1290,86 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 		return resource.CreateNodeGroupResponse{}, err
 	}
 
+	err = validateDataVolumes(req.DataVolumes)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to validate data volumes")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+	nodeGroupDataVolumesJSON, err := json.Marshal(req.DataVolumes)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"dataVolumes": req.DataVolumes,
+		}).WithError(err).Error("failed to marshal node group data volumes")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	err = ValidateUserDataExtensions(req.UserData)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to validate user data")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	err = nodg.validateAdditionalNetworks(ctx, token, cluster, req.AdditionalNetworks)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to validate additional networks")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+	nodeGroupNetworksJSON, err := json.Marshal(req.AdditionalNetworks)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to marshal node group additional networks")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+	nodeGroupUserDataJSON, err := json.Marshal(req.UserData)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to marshal node group user data")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	err = validateAvailabilityZones(req.AvailabilityZones)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to validate availability zones")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+	nodeGroupZonesJSON, err := json.Marshal(req.AvailabilityZones)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to marshal node group availability zones")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	serverGroupPolicy, err := ValidateServerGroupPolicy(req.ServerGroupPolicy)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"serverGroupPolicy": req.ServerGroupPolicy,
+		}).WithError(err).Error("failed to validate server group policy")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	imageRef, err := nodg.imageService.ValidateImage(ctx, token, cluster.ClusterProjectUUID, req.ImageRef)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"imageRef": req.ImageRef,
+		}).WithError(err).Error("failed to validate image")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
 	createServerGroupReq := request.CreateServerGroupRequest{
 		ServerGroup: request.ServerGroup{
 			Name:   fmt.Sprintf("%v-%v-worker-server-group", cluster.ClusterName, req.NodeGroupName),
-			Policy: "soft-anti-affinity",
+			Policy: serverGroupPolicy,
 		},
 	}
 
@@ -635,6 +1424,15 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 		return resource.CreateNodeGroupResponse{}, err
 	}
 
+	userDataExtensions, err := getUserDataExtensions(cluster.ClusterUserData, nodeGroupUserDataJSON)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterName":   cluster.ClusterName,
+			"nodeGroupName": req.NodeGroupName,
+		}).WithError(err).Error("failed to unmarshal user data extensions")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
 	rke2WorkerInitScript, err := GenerateUserDataFromTemplate("false",
 		WorkerServerType,
 		cluster.ClusterRegisterToken,
@@ -655,6 +1453,9 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 		"",
 		"",
 		config.GlobalConfig.GetPublicNetworkIDConfig().PublicNetworkID,
+		nil,
+		clusterRKE2NetworkSettings(cluster),
+		userDataExtensions,
 	)
 	if err != nil {
 		nodg.logger.WithFields(logrus.Fields{
@@ -677,10 +1478,10 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 	WorkerRequest := &request.CreateComputeRequest{
 		Server: request.Server{
 			Name:             "ServerName",
-			ImageRef:         config.GlobalConfig.GetImageRefConfig().ImageRef,
+			ImageRef:         imageRef,
 			FlavorRef:        req.NodeFlavorUUID,
 			KeyName:          cluster.ClusterNodeKeypairName,
-			AvailabilityZone: "nova",
+			AvailabilityZone: DefaultAvailabilityZone,
 			SecurityGroups: []request.SecurityGroups{
 				{Name: securityGroupResp.SecurityGroup.Name},
 				{Name: getClusterSharedSecurityGroup.SecurityGroup.Name},
@@ -691,7 +1492,7 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 					DestinationType:     "volume",
 					DeleteOnTermination: true,
 					SourceType:          "image",
-					UUID:                config.GlobalConfig.GetImageRefConfig().ImageRef,
+					UUID:                imageRef,
 					VolumeSize:          req.NodeDiskSize,
 				},
 			},
@@ -701,6 +1502,7 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 			Group: serverGroupResp.ServerGroup.ID,
 		},
 	}
+	WorkerRequest.Server.BlockDeviceMappingV2 = append(WorkerRequest.Server.BlockDeviceMappingV2, dataVolumeBlockDeviceMappings(req.DataVolumes)...)
 
 	subnetIDSArr := []string{}
 	err = json.Unmarshal(cluster.ClusterSubnets, &subnetIDSArr)
@@ -728,18 +1530,21 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 		return resource.CreateNodeGroupResponse{}, err
 	}
 
+	clusterSubnets, err := nodg.networkService.GetClusterSubnets(ctx, token, subnetIDSArr)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterName": cluster.ClusterName,
+		}).WithError(err).Error("failed to get cluster subnets")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
 	for i := 1; i <= req.NodeGroupMinSize; i++ {
-		randSubnetId := GetRandomStringFromArray(subnetIDSArr)
 		portRequest := &request.CreateNetworkPortRequest{
 			Port: request.Port{
-				NetworkID:    getNetworkIdResp.Subnet.NetworkID,
-				Name:         fmt.Sprintf("%v-%s-port", cluster.ClusterName, req.NodeGroupName),
-				AdminStateUp: true,
-				FixedIps: []request.FixedIp{
-					{
-						SubnetID: randSubnetId,
-					},
-				},
+				NetworkID:      getNetworkIdResp.Subnet.NetworkID,
+				Name:           fmt.Sprintf("%v-%s-port", cluster.ClusterName, req.NodeGroupName),
+				AdminStateUp:   true,
+				FixedIps:       clusterSubnets.FixedIps(),
 				SecurityGroups: []string{securityGroupResp.SecurityGroup.ID, getClusterSharedSecurityGroup.SecurityGroup.ID},
 			},
 		}
@@ -751,13 +1556,20 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 			}).WithError(err).Error("failed to create network port")
 			return resource.CreateNodeGroupResponse{}, err
 		}
-		WorkerRequest.Server.Networks = []request.Networks{
-			{Port: portResp.Port.ID},
+		additionalPorts, err := nodg.createAdditionalNetworkPorts(ctx, token, cluster, fmt.Sprintf("%v-%s-port", cluster.ClusterName, req.NodeGroupName), req.AdditionalNetworks)
+		if err != nil {
+			nodg.deleteNetworkPorts(ctx, token, cluster.ClusterUUID, []string{portResp.Port.ID})
+			return resource.CreateNodeGroupResponse{}, err
 		}
+		WorkerRequest.Server.Networks = append([]request.Networks{
+			{Port: portResp.Port.ID},
+		}, additionalPorts...)
 		WorkerRequest.Server.Name = fmt.Sprintf("%s-%s-%s", cluster.ClusterName, req.NodeGroupName, uuid.New().String()[:8])
+		WorkerRequest.Server.AvailabilityZone = nodeAvailabilityZone(req.AvailabilityZones, i-1)
 
 		_, err = nodg.computeService.CreateCompute(ctx, token, *WorkerRequest)
 		if err != nil {
+			nodg.deleteNetworkPorts(ctx, token, cluster.ClusterUUID, networkPortIDs(WorkerRequest.Server.Networks))
 			return resource.CreateNodeGroupResponse{}, err
 		}
 	}
@@ -775,6 +1587,13 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 		NodeGroupsType:         NodeGroupWorkerType,
 		NodeGroupSecurityGroup: securityGroupResp.SecurityGroup.ID,
 		NodeGroupsStatus:       NodeGroupActiveStatus,
+		NodeGroupAutoRepair:    req.AutoRepair,
+		ServerGroupPolicy:      serverGroupPolicy,
+		NodeImageRef:           imageRef,
+		NodeGroupDataVolumes:   nodeGroupDataVolumesJSON,
+		NodeGroupUserData:      nodeGroupUserDataJSON,
+		NodeGroupNetworks:      nodeGroupNetworksJSON,
+		NodeGroupZones:         nodeGroupZonesJSON,
 		IsHidden:               false,
 		NodeGroupCreateDate:    time.Now(),
 	})
@@ -787,6 +1606,8 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 		return resource.CreateNodeGroupResponse{}, err
 	}
 
+	go nodg.checkServerGroupPlacement(context.Background(), token, cluster, cluster.ClusterName+"-"+req.NodeGroupName, serverGroupResp.ServerGroup.ID, serverGroupPolicy)
+
 	err = nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
 		ClusterUUID: cluster.ClusterUUID,
 		ProjectUUID: cluster.ClusterProjectUUID,
@@ -808,7 +1629,333 @@ func (nodg *nodeGroupsService) CreateNodeGroup(ctx context.Context, authToken, c
 	}, nil
 }
 
-func (nodg *nodeGroupsService) DeleteNodeGroup(ctx context.Context, authToken, clusterID, nodeGroupID string) error {
+// CloneNodeGroup creates a new worker node group initialised from the stored
+// settings of the source node group. The new node group gets its own security
+// group and server group through CreateNodeGroup.
+func (nodg *nodeGroupsService) CloneNodeGroup(ctx context.Context, authToken, clusterID, nodeGroupID string, req request.CloneNodeGroupRequest) (resource.CreateNodeGroupResponse, error) {
+	token := strings.Clone(authToken)
+
+	cluster, err := nodg.repository.Cluster().GetClusterByUUID(ctx, clusterID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterID": clusterID,
+		}).WithError(err).Error("failed to get cluster by uuid")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+	if cluster == nil || cluster.ClusterProjectUUID == "" {
+		return resource.CreateNodeGroupResponse{}, fmt.Errorf("failed to get cluster")
+	}
+
+	err = nodg.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterProjectUUID": cluster.ClusterProjectUUID,
+		}).WithError(err).Error("failed to check auth token")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	source, err := nodg.repository.NodeGroups().GetNodeGroupByUUID(ctx, nodeGroupID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to get node group by uuid")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+	if source == nil || source.ClusterUUID != cluster.ClusterUUID || source.NodeGroupsStatus == NodeGroupDeletedStatus {
+		return resource.CreateNodeGroupResponse{}, fmt.Errorf("failed to get node group")
+	}
+	if source.NodeGroupsType != NodeGroupWorkerType {
+		return resource.CreateNodeGroupResponse{}, fmt.Errorf("only worker node groups can be cloned")
+	}
+	sourceName := strings.TrimPrefix(source.NodeGroupName, cluster.ClusterName+"-")
+	if req.NodeGroupName == "" || req.NodeGroupName == sourceName {
+		return resource.CreateNodeGroupResponse{}, fmt.Errorf("a new node group name is required")
+	}
+
+	createReq, err := cloneNodeGroupRequest(*source, sourceName, req)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to read stored node group settings")
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	resp, err := nodg.CreateNodeGroup(ctx, token, clusterID, createReq)
+	if err != nil {
+		return resource.CreateNodeGroupResponse{}, err
+	}
+
+	err = nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
+		ClusterUUID: cluster.ClusterUUID,
+		ProjectUUID: cluster.ClusterProjectUUID,
+		Event:       fmt.Sprintf("Node group %s cloned from %s", req.NodeGroupName, source.NodeGroupName),
+		CreateDate:  time.Now(),
+	})
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to create audit log")
+	}
+
+	return resp, nil
+}
+
+// cloneNodeGroupRequest builds the create request from the stored node group
+// and applies the overrides of the clone request on top of it. sourceName is
+// the name of the source without the cluster name prefix of the stored name.
+func cloneNodeGroupRequest(source model.NodeGroups, sourceName string, req request.CloneNodeGroupRequest) (request.CreateNodeGroupRequest, error) {
+	createReq := request.CreateNodeGroupRequest{
+		NodeGroupName:     req.NodeGroupName,
+		NodeFlavorUUID:    source.NodeFlavorUUID,
+		NodeDiskSize:      source.NodeDiskSize,
+		NodeGroupMinSize:  source.NodeGroupMinSize,
+		NodeGroupMaxSize:  source.NodeGroupMaxSize,
+		AutoRepair:        source.NodeGroupAutoRepair,
+		ServerGroupPolicy: source.ServerGroupPolicy,
+		ImageRef:          source.NodeImageRef,
+	}
+
+	if source.NodeGroupLabels != nil {
+		err := json.Unmarshal(source.NodeGroupLabels, &createReq.NodeGroupLabels)
+		if err != nil {
+			return request.CreateNodeGroupRequest{}, err
+		}
+	}
+	// The default label carries the node group name, it must follow the clone.
+	for i, label := range createReq.NodeGroupLabels {
+		if label == "nodegroup-name="+sourceName {
+			createReq.NodeGroupLabels[i] = "nodegroup-name=" + req.NodeGroupName
+		}
+	}
+	if source.NodeGroupTaints != nil {
+		err := json.Unmarshal(source.NodeGroupTaints, &createReq.NodeGroupTaints)
+		if err != nil {
+			return request.CreateNodeGroupRequest{}, err
+		}
+	}
+	if source.NodeGroupUserData != nil {
+		err := json.Unmarshal(source.NodeGroupUserData, &createReq.UserData)
+		if err != nil {
+			return request.CreateNodeGroupRequest{}, err
+		}
+	}
+
+	var err error
+	createReq.DataVolumes, err = nodeGroupDataVolumes(source)
+	if err != nil {
+		return request.CreateNodeGroupRequest{}, err
+	}
+	createReq.AdditionalNetworks, err = nodeGroupAdditionalNetworks(source)
+	if err != nil {
+		return request.CreateNodeGroupRequest{}, err
+	}
+	createReq.AvailabilityZones, err = nodeGroupAvailabilityZones(source)
+	if err != nil {
+		return request.CreateNodeGroupRequest{}, err
+	}
+
+	if req.NodeFlavorUUID != nil {
+		createReq.NodeFlavorUUID = *req.NodeFlavorUUID
+	}
+	if req.NodeDiskSize != nil {
+		createReq.NodeDiskSize = *req.NodeDiskSize
+	}
+	if req.NodeGroupLabels != nil {
+		createReq.NodeGroupLabels = req.NodeGroupLabels
+	}
+	if req.NodeGroupTaints != nil {
+		createReq.NodeGroupTaints = req.NodeGroupTaints
+	}
+	if req.NodeGroupMinSize != nil {
+		createReq.NodeGroupMinSize = *req.NodeGroupMinSize
+	}
+	if req.NodeGroupMaxSize != nil {
+		createReq.NodeGroupMaxSize = *req.NodeGroupMaxSize
+	}
+	if req.AutoRepair != nil {
+		createReq.AutoRepair = *req.AutoRepair
+	}
+	if req.ServerGroupPolicy != nil {
+		createReq.ServerGroupPolicy = *req.ServerGroupPolicy
+	}
+	if req.ImageRef != nil {
+		createReq.ImageRef = *req.ImageRef
+	}
+	if req.DataVolumes != nil {
+		createReq.DataVolumes = req.DataVolumes
+	}
+	if req.UserData != nil {
+		createReq.UserData = *req.UserData
+	}
+	if req.AdditionalNetworks != nil {
+		createReq.AdditionalNetworks = req.AdditionalNetworks
+	}
+	if req.AvailabilityZones != nil {
+		createReq.AvailabilityZones = req.AvailabilityZones
+	}
+
+	return createReq, nil
+}
+
+func (nodg *nodeGroupsService) GetNodeGroupDeleteImpact(ctx context.Context, authToken, clusterID, nodeGroupID string) (resource.NodeGroupDeleteImpact, error) {
+	token := strings.Clone(authToken)
+
+	cluster, err := nodg.repository.Cluster().GetClusterByUUID(ctx, clusterID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterID": clusterID,
+		}).WithError(err).Error("failed to get cluster by uuid")
+		return resource.NodeGroupDeleteImpact{}, err
+	}
+	if cluster == nil || cluster.ClusterProjectUUID == "" {
+		return resource.NodeGroupDeleteImpact{}, fmt.Errorf("failed to get cluster")
+	}
+
+	err = nodg.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterProjectUUID": cluster.ClusterProjectUUID,
+		}).WithError(err).Error("failed to check auth token")
+		return resource.NodeGroupDeleteImpact{}, err
+	}
+
+	nodeGroup, err := nodg.repository.NodeGroups().GetNodeGroupByUUID(ctx, nodeGroupID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to get node group by uuid")
+		return resource.NodeGroupDeleteImpact{}, err
+	}
+	if nodeGroup == nil || nodeGroup.ClusterUUID != cluster.ClusterUUID || nodeGroup.NodeGroupsStatus == NodeGroupDeletedStatus {
+		return resource.NodeGroupDeleteImpact{}, fmt.Errorf("failed to get node group")
+	}
+
+	computes, err := nodg.computeService.GetInstances(ctx, token, nodeGroup.NodeGroupUUID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+		}).WithError(err).Error("failed to get instances")
+		return resource.NodeGroupDeleteImpact{}, err
+	}
+
+	return nodg.nodeGroupDeleteImpact(ctx, nodeGroup, computes), nil
+}
+
+// nodeGroupDeleteImpact checks whether the node group can be deleted safely.
+// Master node groups and the last schedulable worker node group are never
+// deleted, running workloads on the nodes only require the caller to force.
+func (nodg *nodeGroupsService) nodeGroupDeleteImpact(ctx context.Context, nodeGroup *model.NodeGroups, computes []resource.Servers) resource.NodeGroupDeleteImpact {
+	impact := resource.NodeGroupDeleteImpact{
+		ClusterID:     nodeGroup.ClusterUUID,
+		NodeGroupID:   nodeGroup.NodeGroupUUID,
+		NodeGroupName: nodeGroup.NodeGroupName,
+		NodeGroupType: nodeGroup.NodeGroupsType,
+		Blockers:      []string{},
+		Warnings:      []string{},
+		Nodes:         []string{},
+		Pods:          []resource.NodeGroupPod{},
+	}
+
+	if nodeGroup.NodeGroupsType == NodeGroupMasterType {
+		impact.Blockers = append(impact.Blockers, "master node groups can not be deleted")
+	} else {
+		schedulable, err := nodg.hasOtherSchedulableWorkerGroup(ctx, nodeGroup)
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"nodeGroupUUID": nodeGroup.NodeGroupUUID,
+			}).WithError(err).Error("failed to get worker node groups")
+			impact.Blockers = append(impact.Blockers, "failed to check the remaining worker node groups")
+		} else if !schedulable {
+			impact.Blockers = append(impact.Blockers, "the last schedulable worker node group can not be deleted")
+		}
+
+		ingressLoadBalancer, err := nodg.repository.IngressLoadBalancers().GetIngressLoadBalancerByClusterUUID(ctx, nodeGroup.ClusterUUID)
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"clusterUUID": nodeGroup.ClusterUUID,
+			}).WithError(err).Error("failed to get ingress load balancer")
+			impact.Blockers = append(impact.Blockers, "failed to check the ingress load balancer")
+		} else if ingressLoadBalancer != nil && ingressLoadBalancer.NodeGroupUUID == nodeGroup.NodeGroupUUID {
+			impact.Blockers = append(impact.Blockers, "the node group is the target of the ingress load balancer")
+		}
+	}
+
+	for _, server := range computes {
+		impact.Nodes = append(impact.Nodes, server.Name)
+
+		pods, err := nodg.kubernetesService.GetNodePods(ctx, nodeGroup.ClusterUUID, strings.ToLower(server.Name))
+		if err != nil {
+			nodg.logger.WithFields(logrus.Fields{
+				"clusterUUID": nodeGroup.ClusterUUID,
+				"nodeName":    server.Name,
+			}).WithError(err).Warn("failed to get kubernetes pods of node")
+			impact.Warnings = append(impact.Warnings, fmt.Sprintf("pods of node %s could not be checked", server.Name))
+			impact.RequiresForce = true
+			continue
+		}
+
+		for _, pod := range pods {
+			if !IsKubernetesWorkloadPod(pod) {
+				continue
+			}
+			nodeGroupPod := resource.NodeGroupPod{
+				Namespace: pod.Metadata.Namespace,
+				Name:      pod.Metadata.Name,
+				NodeName:  pod.Spec.NodeName,
+			}
+			if len(pod.Metadata.OwnerReferences) > 0 {
+				nodeGroupPod.OwnerKind = pod.Metadata.OwnerReferences[0].Kind
+			}
+			impact.Pods = append(impact.Pods, nodeGroupPod)
+		}
+	}
+
+	if len(impact.Pods) > 0 {
+		impact.Warnings = append(impact.Warnings, fmt.Sprintf("%d workload pods will be evicted", len(impact.Pods)))
+		impact.RequiresForce = true
+	}
+	impact.Deletable = len(impact.Blockers) == 0
+
+	return impact
+}
+
+// hasOtherSchedulableWorkerGroup reports whether the cluster keeps an active
+// worker node group without NoSchedule or NoExecute taints.
+func (nodg *nodeGroupsService) hasOtherSchedulableWorkerGroup(ctx context.Context, nodeGroup *model.NodeGroups) (bool, error) {
+	workerGroups, err := nodg.repository.NodeGroups().GetNodeGroupsByClusterUUID(ctx, nodeGroup.ClusterUUID, NodeGroupWorkerType, NodeGroupActiveStatus)
+	if err != nil {
+		return false, err
+	}
+
+	for _, workerGroup := range workerGroups {
+		if workerGroup.NodeGroupUUID == nodeGroup.NodeGroupUUID {
+			continue
+		}
+
+		taints := []string{}
+		if workerGroup.NodeGroupTaints != nil {
+			err = json.Unmarshal(workerGroup.NodeGroupTaints, &taints)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		schedulable := true
+		for _, taint := range taints {
+			if strings.HasSuffix(taint, ":NoSchedule") || strings.HasSuffix(taint, ":NoExecute") {
+				schedulable = false
+				break
+			}
+		}
+		if schedulable {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (nodg *nodeGroupsService) DeleteNodeGroup(ctx context.Context, authToken, clusterID, nodeGroupID string, force bool) error {
 	token := strings.Clone(authToken)
 	cluster, err := nodg.repository.Cluster().GetClusterByUUID(ctx, clusterID)
 	if err != nil {
@@ -842,6 +1989,15 @@ func (nodg *nodeGroupsService) DeleteNodeGroup(ctx context.Context, authToken, c
 		}).WithError(err).Error("failed to get instances")
 		return err
 	}
+
+	impact := nodg.nodeGroupDeleteImpact(ctx, nodeGroup, computes)
+	if !impact.Deletable {
+		return fmt.Errorf("node group can not be deleted: %s", strings.Join(impact.Blockers, ", "))
+	}
+	if impact.RequiresForce && !force {
+		return fmt.Errorf("node group deletion requires force: %s", strings.Join(impact.Warnings, ", "))
+	}
+
 	for _, server := range computes {
 		serverUUID := strings.Split(server.Id, "/")[len(strings.Split(server.Id, "/"))-1]
 		getNetworkPortID, err := nodg.networkService.GetComputeNetworkPorts(ctx, token, serverUUID)
@@ -859,6 +2015,7 @@ func (nodg *nodeGroupsService) DeleteNodeGroup(ctx context.Context, authToken, c
 				}).WithError(err).Error("failed to delete network port")
 				return err
 			}
+			nodg.deleteNetworkPortResource(ctx, cluster.ClusterUUID, portID)
 		}
 		err = nodg.computeService.DeleteCompute(ctx, token, serverUUID)
 		if err != nil {
@@ -867,6 +2024,7 @@ func (nodg *nodeGroupsService) DeleteNodeGroup(ctx context.Context, authToken, c
 			}).WithError(err).Error("failed to delete compute")
 			return err
 		}
+		go nodg.deleteDataVolumes(context.Background(), token, serverUUID, server.Volumes)
 	}
 	err = nodg.computeService.DeleteServerGroup(ctx, token, nodeGroup.NodeGroupUUID)
 	if err != nil {
@@ -896,6 +2054,13 @@ func (nodg *nodeGroupsService) DeleteNodeGroup(ctx context.Context, authToken, c
 		return err
 	}
 
+	err = nodg.repository.NodeGroupSchedules().DeleteSchedulesByNodeGroupUUID(ctx, nodeGroupID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Warn("failed to delete node group schedules")
+	}
+
 	err = nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
 		ClusterUUID: cluster.ClusterUUID,
 		ProjectUUID: cluster.ClusterProjectUUID,
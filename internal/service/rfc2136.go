@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/resource"
+)
+
+const (
+	tsigFudge = 300
+
+	rfc2136Timeout = 10 * time.Second
+)
+
+type rfc2136DNSProvider struct {
+	logger *logrus.Logger
+}
+
+// NewRFC2136DNSProvider sends dynamic updates to RFC2136_SERVER, signed with
+// the configured TSIG key when RFC2136_TSIG_KEY_NAME is set.
+func NewRFC2136DNSProvider(logger *logrus.Logger) IDNSProvider {
+	return &rfc2136DNSProvider{
+		logger: logger,
+	}
+}
+
+func (p *rfc2136DNSProvider) Name() string {
+	return DNSProviderRFC2136
+}
+
+// CreateRecord adds the RRset of the name, dynamic updates have no record
+// identifiers so the record ID is the name and the type of the RRset. The
+// update requires the RRset to be absent so a record which is not owned by
+// the cluster is never replaced.
+func (p *rfc2136DNSProvider) CreateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error) {
+	rr, err := rfc2136RR(record.Name, record.Type, record.TTL, record.Content)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+
+	update := p.newUpdate()
+	update.RRsetNotUsed([]dns.RR{rr})
+	update.Insert([]dns.RR{rr})
+
+	err = p.send(ctx, update)
+	if err != nil {
+		p.logger.WithError(err).WithField("name", record.Name).Error("failed to create dns record")
+		return resource.DNSRecord{}, err
+	}
+
+	record.Provider = DNSProviderRFC2136
+	record.ID = rfc2136RecordID(record.Name, record.Type)
+	return record, nil
+}
+
+func (p *rfc2136DNSProvider) UpdateRecord(ctx context.Context, record resource.DNSRecord) (resource.DNSRecord, error) {
+	name, recordType, err := parseRFC2136RecordID(record.ID)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+	oldRR, err := rfc2136RRSet(name, recordType)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+	rr, err := rfc2136RR(record.Name, record.Type, record.TTL, record.Content)
+	if err != nil {
+		return resource.DNSRecord{}, err
+	}
+
+	update := p.newUpdate()
+	update.RemoveRRset([]dns.RR{oldRR, rr})
+	update.Insert([]dns.RR{rr})
+
+	err = p.send(ctx, update)
+	if err != nil {
+		p.logger.WithError(err).WithField("recordID", record.ID).Error("failed to update dns record")
+		return resource.DNSRecord{}, err
+	}
+
+	record.Provider = DNSProviderRFC2136
+	record.ID = rfc2136RecordID(record.Name, record.Type)
+	return record, nil
+}
+
+func (p *rfc2136DNSProvider) DeleteRecord(ctx context.Context, recordID string) error {
+	name, recordType, err := parseRFC2136RecordID(recordID)
+	if err != nil {
+		return err
+	}
+	rr, err := rfc2136RRSet(name, recordType)
+	if err != nil {
+		return err
+	}
+
+	update := p.newUpdate()
+	update.RemoveRRset([]dns.RR{rr})
+
+	err = p.send(ctx, update)
+	if err != nil {
+		p.logger.WithError(err).WithField("recordID", recordID).Error("failed to delete dns record")
+		return err
+	}
+
+	return nil
+}
+
+// ListRecords is not supported, dynamic updates have no way to list a zone and
+// the records carry no comment which identifies the cluster. Orphan record
+// garbage collection is therefore not available with this provider.
+func (p *rfc2136DNSProvider) ListRecords(ctx context.Context) ([]resource.DNSRecord, error) {
+	return nil, fmt.Errorf("listing records is not supported by the %s dns provider", DNSProviderRFC2136)
+}
+
+func (p *rfc2136DNSProvider) zone() string {
+	dnsConfig := config.GlobalConfig.GetDNSConfig()
+	if dnsConfig.RFC2136Zone != "" {
+		return dnsConfig.RFC2136Zone
+	}
+	return dnsConfig.Domain
+}
+
+func (p *rfc2136DNSProvider) newUpdate() *dns.Msg {
+	update := new(dns.Msg)
+	update.SetUpdate(dns.Fqdn(p.zone()))
+	return update
+}
+
+// send writes the update over TCP, which avoids truncation handling, and
+// checks the response code of the server. Signed updates require a response
+// with a valid TSIG of the same key.
+func (p *rfc2136DNSProvider) send(ctx context.Context, update *dns.Msg) error {
+	dnsConfig := config.GlobalConfig.GetDNSConfig()
+
+	client := &dns.Client{Net: "tcp", Timeout: rfc2136Timeout}
+	signed := dnsConfig.RFC2136TSIGKeyName != ""
+	if signed {
+		algorithm, err := rfc2136TSIGAlgorithm(dnsConfig.RFC2136TSIGAlgorithm)
+		if err != nil {
+			return err
+		}
+		keyName := dns.Fqdn(strings.ToLower(dnsConfig.RFC2136TSIGKeyName))
+		client.TsigSecret = map[string]string{keyName: dnsConfig.RFC2136TSIGSecret}
+		update.SetTsig(keyName, algorithm, tsigFudge, time.Now().Unix())
+	}
+
+	server := dnsConfig.RFC2136Server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	resp, _, err := client.ExchangeContext(ctx, update, server)
+	if err != nil {
+		return err
+	}
+	if signed && resp.IsTsig() == nil {
+		return fmt.Errorf("dns update response is not signed")
+	}
+	if resp.Rcode == dns.RcodeYXRrset {
+		return fmt.Errorf("dns record already exists")
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("dns update refused by server, rcode: %s", dns.RcodeToString[resp.Rcode])
+	}
+
+	return nil
+}
+
+func rfc2136TSIGAlgorithm(algorithm string) (string, error) {
+	switch strings.ToLower(strings.TrimSuffix(algorithm, ".")) {
+	case "hmac-sha1":
+		return dns.HmacSHA1, nil
+	case "hmac-sha256":
+		return dns.HmacSHA256, nil
+	case "hmac-sha512":
+		return dns.HmacSHA512, nil
+	default:
+		return "", fmt.Errorf("tsig algorithm %s is not supported", algorithm)
+	}
+}
+
+// rfc2136RR returns the A or AAAA record of the name.
+func rfc2136RR(name, recordType string, ttl int, address string) (dns.RR, error) {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip address %s", address)
+	}
+	header := dns.RR_Header{Name: dns.Fqdn(name), Class: dns.ClassINET, Ttl: uint32(ttl)}
+
+	switch recordType {
+	case DNSRecordTypeA:
+		if ip.To4() == nil {
+			return nil, fmt.Errorf("ip address %s does not match record type %s", address, recordType)
+		}
+		header.Rrtype = dns.TypeA
+		return &dns.A{Hdr: header, A: ip.To4()}, nil
+	case DNSRecordTypeAAAA:
+		if ip.To4() != nil {
+			return nil, fmt.Errorf("ip address %s does not match record type %s", address, recordType)
+		}
+		header.Rrtype = dns.TypeAAAA
+		return &dns.AAAA{Hdr: header, AAAA: ip.To16()}, nil
+	default:
+		return nil, fmt.Errorf("dns record type %s is not supported", recordType)
+	}
+}
+
+// rfc2136RRSet returns a record which only identifies the RRset of the name
+// and type, as needed for prerequisites and RRset deletions.
+func rfc2136RRSet(name, recordType string) (dns.RR, error) {
+	rrType, ok := dns.StringToType[recordType]
+	if !ok || (rrType != dns.TypeA && rrType != dns.TypeAAAA) {
+		return nil, fmt.Errorf("dns record type %s is not supported", recordType)
+	}
+	return &dns.ANY{Hdr: dns.RR_Header{Name: dns.Fqdn(name), Rrtype: rrType, Class: dns.ClassINET}}, nil
+}
+
+func rfc2136RecordID(name, recordType string) string {
+	return fmt.Sprintf("%s/%s", strings.TrimSuffix(name, "."), recordType)
+}
+
+func parseRFC2136RecordID(recordID string) (string, string, error) {
+	name, recordType, ok := strings.Cut(recordID, "/")
+	if !ok || name == "" || recordType == "" {
+		return "", "", fmt.Errorf("invalid rfc2136 record id %s", recordID)
+	}
+	return name, recordType, nil
+}
@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vmindtech/vke/config"
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
+	"github.com/vmindtech/vke/internal/repository"
+	"github.com/vmindtech/vke/pkg/cron"
+)
+
+const (
+	DefaultScheduleTimezone   = "UTC"
+	MaxNodeGroupSchedules     = 10
+	MaxScheduleCronExpression = 100
+)
+
+type IScheduledScalingService interface {
+	Start(ctx context.Context)
+	RunSchedules(ctx context.Context)
+}
+
+type scheduledScalingService struct {
+	logger            *logrus.Logger
+	repository        repository.IRepository
+	identityService   IIdentityService
+	nodeGroupsService INodeGroupsService
+
+	// running holds the node groups with a schedule run in progress.
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewScheduledScalingService(l *logrus.Logger, r repository.IRepository, i IIdentityService, ng INodeGroupsService) IScheduledScalingService {
+	return &scheduledScalingService{
+		logger:            l,
+		repository:        r,
+		identityService:   i,
+		nodeGroupsService: ng,
+		running:           map[string]bool{},
+	}
+}
+
+// Start runs the scheduled scaling loop until the context is cancelled. It
+// does nothing when scheduled scaling is disabled in the configuration.
+func (ss *scheduledScalingService) Start(ctx context.Context) {
+	scheduledScalingConfig := config.GlobalConfig.GetScheduledScalingConfig()
+	if !scheduledScalingConfig.Enabled {
+		ss.logger.Info("scheduled scaling is disabled")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(scheduledScalingConfig.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	ss.logger.WithFields(logrus.Fields{
+		"intervalSeconds": scheduledScalingConfig.IntervalSeconds,
+	}).Info("scheduled scaling started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ss.RunSchedules(ctx)
+		}
+	}
+}
+
+// dueSchedule is a schedule with its latest activation which is not after
+// the current run.
+type dueSchedule struct {
+	schedule   model.NodeGroupSchedule
+	activation time.Time
+}
+
+// RunSchedules applies the due schedules, each node group in its own
+// goroutine. Only the latest due activation of a node group is applied, the
+// other due schedules of the node group are superseded by it. Node groups
+// which are still scaling are left for a later tick.
+func (ss *scheduledScalingService) RunSchedules(ctx context.Context) {
+	schedules, err := ss.repository.NodeGroupSchedules().GetEnabledSchedules(ctx)
+	if err != nil {
+		ss.logger.WithError(err).Error("failed to get node group schedules")
+		return
+	}
+
+	now := time.Now()
+	due := map[string][]dueSchedule{}
+	for _, schedule := range schedules {
+		cronSchedule, location, err := parseNodeGroupSchedule(schedule.CronExpression, schedule.Timezone)
+		if err != nil {
+			ss.logger.WithError(err).WithFields(logrus.Fields{
+				"scheduleUUID": schedule.ScheduleUUID,
+			}).Error("failed to parse node group schedule")
+			continue
+		}
+
+		// Missed activations, e.g. while vke was down, are applied once.
+		from := schedule.CreateDate
+		if schedule.LastRunDate != nil {
+			from = *schedule.LastRunDate
+		}
+		next := cronSchedule.Next(from.In(location))
+		if next.IsZero() || next.After(now) {
+			continue
+		}
+
+		due[schedule.NodeGroupUUID] = append(due[schedule.NodeGroupUUID], dueSchedule{
+			schedule:   schedule,
+			activation: latestActivation(cronSchedule, next, now),
+		})
+	}
+
+	for nodeGroupUUID, dueSchedules := range due {
+		if ss.nodeGroupsService.IsScaling(nodeGroupUUID) || !ss.startRun(nodeGroupUUID) {
+			continue
+		}
+
+		latest := 0
+		for i := range dueSchedules {
+			if dueSchedules[i].activation.After(dueSchedules[latest].activation) {
+				latest = i
+			}
+		}
+		superseded := []model.NodeGroupSchedule{}
+		for i := range dueSchedules {
+			if i != latest {
+				superseded = append(superseded, dueSchedules[i].schedule)
+			}
+		}
+
+		go func(nodeGroupUUID string, schedule model.NodeGroupSchedule, superseded []model.NodeGroupSchedule) {
+			defer ss.finishRun(nodeGroupUUID)
+			ss.runSchedule(context.Background(), schedule, superseded, now)
+		}(nodeGroupUUID, dueSchedules[latest].schedule, superseded)
+	}
+}
+
+// latestActivation returns the last activation of the schedule which is not
+// after now, next is its first due activation.
+func latestActivation(cronSchedule *cron.Schedule, next, now time.Time) time.Time {
+	for {
+		following := cronSchedule.Next(next)
+		if following.IsZero() || following.After(now) {
+			return next
+		}
+		next = following
+	}
+}
+
+func (ss *scheduledScalingService) startRun(nodeGroupUUID string) bool {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if ss.running[nodeGroupUUID] {
+		return false
+	}
+	ss.running[nodeGroupUUID] = true
+	return true
+}
+
+func (ss *scheduledScalingService) finishRun(nodeGroupUUID string) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	delete(ss.running, nodeGroupUUID)
+}
+
+// markRun records the run of the schedule and of the schedules it superseded.
+// Failed runs are recorded as well so a failing schedule is not retried on
+// every tick, the failure is visible in the audit log instead.
+func (ss *scheduledScalingService) markRun(ctx context.Context, schedules []model.NodeGroupSchedule, now time.Time) {
+	for _, schedule := range schedules {
+		err := ss.repository.NodeGroupSchedules().UpdateScheduleLastRun(ctx, schedule.ScheduleUUID, now)
+		if err != nil {
+			ss.logger.WithError(err).WithFields(logrus.Fields{
+				"scheduleUUID": schedule.ScheduleUUID,
+			}).Error("failed to update node group schedule last run")
+		}
+	}
+}
+
+func (ss *scheduledScalingService) runSchedule(ctx context.Context, schedule model.NodeGroupSchedule, superseded []model.NodeGroupSchedule, now time.Time) {
+	schedules := append([]model.NodeGroupSchedule{schedule}, superseded...)
+
+	cluster, err := ss.repository.Cluster().GetClusterByUUID(ctx, schedule.ClusterUUID)
+	if err != nil || cluster == nil {
+		ss.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": schedule.ClusterUUID,
+		}).Error("failed to get cluster")
+		return
+	}
+	if cluster.ClusterStatus != ActiveClusterStatus {
+		ss.markRun(ctx, schedules, now)
+		ss.createAuditLog(ctx, cluster, fmt.Sprintf("Scheduled scaling %s skipped, cluster is not active", schedule.ScheduleUUID))
+		return
+	}
+
+	token, err := ss.identityService.GetServiceToken(ctx, cluster.ClusterProjectUUID)
+	if err != nil {
+		ss.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to get service token")
+		ss.markRun(ctx, schedules, now)
+		ss.createAuditLog(ctx, cluster, fmt.Sprintf("Scheduled scaling %s failed, could not get service token", schedule.ScheduleUUID))
+		return
+	}
+
+	// Schedules lower the size unattended, extra nodes are drained instead of
+	// rejecting the run.
+	req := request.UpdateNodeGroupRequest{ScaleInPolicy: NodeGroupScaleInDrain}
+	if schedule.MinSize != nil {
+		minNodes := uint32(*schedule.MinSize)
+		req.MinNodes = &minNodes
+	}
+	if schedule.MaxSize != nil {
+		maxNodes := uint32(*schedule.MaxSize)
+		req.MaxNodes = &maxNodes
+	}
+	if schedule.DesiredSize != nil {
+		desiredNodes := uint32(*schedule.DesiredSize)
+		req.DesiredNodes = &desiredNodes
+	}
+
+	resp, err := ss.nodeGroupsService.UpdateNodeGroups(ctx, token, schedule.ClusterUUID, schedule.NodeGroupUUID, req)
+	if errors.Is(err, errNodeGroupScaling) {
+		// A size change started since the tick, the run stays due.
+		return
+	}
+	ss.markRun(ctx, schedules, now)
+	if err != nil {
+		ss.logger.WithError(err).WithFields(logrus.Fields{
+			"scheduleUUID":  schedule.ScheduleUUID,
+			"nodeGroupUUID": schedule.NodeGroupUUID,
+		}).Error("failed to apply node group schedule")
+		ss.createAuditLog(ctx, cluster, fmt.Sprintf("Scheduled scaling %s failed for node group %s: %v", schedule.ScheduleUUID, schedule.NodeGroupUUID, err))
+		return
+	}
+
+	ss.logger.WithFields(logrus.Fields{
+		"scheduleUUID":  schedule.ScheduleUUID,
+		"nodeGroupUUID": schedule.NodeGroupUUID,
+	}).Info("node group schedule applied")
+	ss.createAuditLog(ctx, cluster, fmt.Sprintf("Scheduled scaling %s applied to node group %s: %s", schedule.ScheduleUUID, schedule.NodeGroupUUID, describeScheduleTarget(schedule, resp)))
+	for _, s := range superseded {
+		ss.createAuditLog(ctx, cluster, fmt.Sprintf("Scheduled scaling %s superseded by %s for node group %s", s.ScheduleUUID, schedule.ScheduleUUID, schedule.NodeGroupUUID))
+	}
+}
+
+func (ss *scheduledScalingService) createAuditLog(ctx context.Context, cluster *model.Cluster, event string) {
+	err := ss.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
+		ClusterUUID: cluster.ClusterUUID,
+		ProjectUUID: cluster.ClusterProjectUUID,
+		Event:       event,
+		CreateDate:  time.Now(),
+	})
+	if err != nil {
+		ss.logger.WithError(err).WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).Error("failed to create audit log")
+	}
+}
+
+func describeScheduleTarget(schedule model.NodeGroupSchedule, resp resource.UpdateNodeGroupResponse) string {
+	target := []string{fmt.Sprintf("min=%d", resp.MinSize), fmt.Sprintf("max=%d", resp.MaxSize)}
+	if schedule.DesiredSize != nil {
+		target = append(target, fmt.Sprintf("desired=%d", *schedule.DesiredSize))
+	}
+	return strings.Join(target, ", ")
+}
+
+func parseNodeGroupSchedule(cronExpression, timezone string) (*cron.Schedule, *time.Location, error) {
+	if len(cronExpression) > MaxScheduleCronExpression {
+		return nil, nil, fmt.Errorf("cron expression is too long")
+	}
+	cronSchedule, err := cron.Parse(cronExpression)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if timezone == "" {
+		timezone = DefaultScheduleTimezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timezone %q", timezone)
+	}
+
+	return cronSchedule, location, nil
+}
+
+func validateNodeGroupScheduleSizes(req request.CreateNodeGroupScheduleRequest) error {
+	if (req.MinNodes == nil) != (req.MaxNodes == nil) {
+		return fmt.Errorf("minNodes and maxNodes must be set together")
+	}
+	if req.MinNodes == nil && req.DesiredNodes == nil {
+		return fmt.Errorf("schedule must set minNodes and maxNodes or desiredNodes")
+	}
+	if req.MinNodes != nil && *req.MinNodes > *req.MaxNodes {
+		return fmt.Errorf("minNodes can not be greater than maxNodes")
+	}
+	if req.MinNodes != nil && req.DesiredNodes != nil && (*req.DesiredNodes < *req.MinNodes || *req.DesiredNodes > *req.MaxNodes) {
+		return fmt.Errorf("desiredNodes must be between minNodes and maxNodes")
+	}
+	return nil
+}
+
+func nodeGroupScheduleResponse(schedule model.NodeGroupSchedule) resource.NodeGroupSchedule {
+	resp := resource.NodeGroupSchedule{
+		ScheduleUUID:   schedule.ScheduleUUID,
+		ClusterUUID:    schedule.ClusterUUID,
+		NodeGroupUUID:  schedule.NodeGroupUUID,
+		CronExpression: schedule.CronExpression,
+		Timezone:       schedule.Timezone,
+		MinSize:        schedule.MinSize,
+		MaxSize:        schedule.MaxSize,
+		DesiredSize:    schedule.DesiredSize,
+		Enabled:        schedule.Enabled,
+		LastRunDate:    schedule.LastRunDate,
+		CreateDate:     schedule.CreateDate,
+	}
+
+	cronSchedule, location, err := parseNodeGroupSchedule(schedule.CronExpression, schedule.Timezone)
+	if err == nil && schedule.Enabled {
+		if next := cronSchedule.Next(time.Now().In(location)); !next.IsZero() {
+			resp.NextRunDate = &next
+		}
+	}
+
+	return resp
+}
+
+func uint32ToIntPtr(v *uint32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+// getScheduleNodeGroup returns the worker node group of the cluster after
+// checking the token against the project of the cluster.
+func (nodg *nodeGroupsService) getScheduleNodeGroup(ctx context.Context, token, clusterID, nodeGroupID string) (*model.Cluster, *model.NodeGroups, error) {
+	cluster, err := nodg.repository.Cluster().GetClusterByUUID(ctx, clusterID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterID": clusterID,
+		}).WithError(err).Error("failed to get cluster by uuid")
+		return nil, nil, err
+	}
+	if cluster == nil || cluster.ClusterProjectUUID == "" {
+		return nil, nil, fmt.Errorf("failed to get cluster")
+	}
+
+	err = nodg.identityService.CheckAuthToken(ctx, token, cluster.ClusterProjectUUID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterProjectUUID": cluster.ClusterProjectUUID,
+		}).WithError(err).Error("failed to check auth token")
+		return nil, nil, err
+	}
+
+	nodeGroup, err := nodg.repository.NodeGroups().GetNodeGroupByUUID(ctx, nodeGroupID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to get node group by uuid")
+		return nil, nil, err
+	}
+	if nodeGroup == nil || nodeGroup.ClusterUUID != cluster.ClusterUUID {
+		return nil, nil, fmt.Errorf("failed to get node group")
+	}
+	if nodeGroup.NodeGroupsType != NodeGroupWorkerType {
+		return nil, nil, fmt.Errorf("scheduled scaling is only supported for worker node groups")
+	}
+
+	return cluster, nodeGroup, nil
+}
+
+func (nodg *nodeGroupsService) CreateNodeGroupSchedule(ctx context.Context, authToken, clusterID, nodeGroupID string, req request.CreateNodeGroupScheduleRequest) (resource.NodeGroupSchedule, error) {
+	token := strings.Clone(authToken)
+
+	cluster, nodeGroup, err := nodg.getScheduleNodeGroup(ctx, token, clusterID, nodeGroupID)
+	if err != nil {
+		return resource.NodeGroupSchedule{}, err
+	}
+
+	_, _, err = parseNodeGroupSchedule(req.CronExpression, req.Timezone)
+	if err != nil {
+		return resource.NodeGroupSchedule{}, err
+	}
+	err = validateNodeGroupScheduleSizes(req)
+	if err != nil {
+		return resource.NodeGroupSchedule{}, err
+	}
+
+	schedules, err := nodg.repository.NodeGroupSchedules().GetSchedulesByNodeGroupUUID(ctx, nodeGroup.NodeGroupUUID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to get node group schedules")
+		return resource.NodeGroupSchedule{}, err
+	}
+	if len(schedules) >= MaxNodeGroupSchedules {
+		return resource.NodeGroupSchedule{}, fmt.Errorf("a node group can have at most %d schedules", MaxNodeGroupSchedules)
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = DefaultScheduleTimezone
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	schedule := &model.NodeGroupSchedule{
+		ScheduleUUID:   uuid.New().String(),
+		ClusterUUID:    cluster.ClusterUUID,
+		NodeGroupUUID:  nodeGroup.NodeGroupUUID,
+		CronExpression: req.CronExpression,
+		Timezone:       timezone,
+		MinSize:        uint32ToIntPtr(req.MinNodes),
+		MaxSize:        uint32ToIntPtr(req.MaxNodes),
+		DesiredSize:    uint32ToIntPtr(req.DesiredNodes),
+		Enabled:        enabled,
+		CreateDate:     time.Now(),
+	}
+	err = nodg.repository.NodeGroupSchedules().CreateSchedule(ctx, schedule)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to create node group schedule")
+		return resource.NodeGroupSchedule{}, err
+	}
+
+	err = nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
+		ClusterUUID: cluster.ClusterUUID,
+		ProjectUUID: cluster.ClusterProjectUUID,
+		Event:       fmt.Sprintf("Scaling schedule %s (%s %s) created for node group %s", schedule.ScheduleUUID, schedule.CronExpression, schedule.Timezone, nodeGroup.NodeGroupName),
+		CreateDate:  time.Now(),
+	})
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to create audit log")
+	}
+
+	return nodeGroupScheduleResponse(*schedule), nil
+}
+
+func (nodg *nodeGroupsService) GetNodeGroupSchedules(ctx context.Context, authToken, clusterID, nodeGroupID string) ([]resource.NodeGroupSchedule, error) {
+	token := strings.Clone(authToken)
+
+	_, nodeGroup, err := nodg.getScheduleNodeGroup(ctx, token, clusterID, nodeGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := nodg.repository.NodeGroupSchedules().GetSchedulesByNodeGroupUUID(ctx, nodeGroup.NodeGroupUUID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"nodeGroupID": nodeGroupID,
+		}).WithError(err).Error("failed to get node group schedules")
+		return nil, err
+	}
+
+	resp := []resource.NodeGroupSchedule{}
+	for _, schedule := range schedules {
+		resp = append(resp, nodeGroupScheduleResponse(schedule))
+	}
+
+	return resp, nil
+}
+
+func (nodg *nodeGroupsService) DeleteNodeGroupSchedule(ctx context.Context, authToken, clusterID, nodeGroupID, scheduleID string) error {
+	token := strings.Clone(authToken)
+
+	cluster, nodeGroup, err := nodg.getScheduleNodeGroup(ctx, token, clusterID, nodeGroupID)
+	if err != nil {
+		return err
+	}
+
+	schedule, err := nodg.repository.NodeGroupSchedules().GetScheduleByUUID(ctx, scheduleID)
+	if err != nil || schedule.NodeGroupUUID != nodeGroup.NodeGroupUUID {
+		nodg.logger.WithFields(logrus.Fields{
+			"scheduleID": scheduleID,
+		}).WithError(err).Error("failed to get node group schedule")
+		return fmt.Errorf("failed to get node group schedule")
+	}
+
+	err = nodg.repository.NodeGroupSchedules().DeleteSchedule(ctx, scheduleID)
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"scheduleID": scheduleID,
+		}).WithError(err).Error("failed to delete node group schedule")
+		return err
+	}
+
+	err = nodg.repository.AuditLog().CreateAuditLog(ctx, &model.AuditLog{
+		ClusterUUID: cluster.ClusterUUID,
+		ProjectUUID: cluster.ClusterProjectUUID,
+		Event:       fmt.Sprintf("Scaling schedule %s deleted from node group %s", scheduleID, nodeGroup.NodeGroupName),
+		CreateDate:  time.Now(),
+	})
+	if err != nil {
+		nodg.logger.WithFields(logrus.Fields{
+			"clusterUUID": cluster.ClusterUUID,
+		}).WithError(err).Error("failed to create audit log")
+	}
+
+	return nil
+}
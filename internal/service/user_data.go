@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/vmindtech/vke/internal/dto/request"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MaxUserDataPartSize is the size limit of a single user data snippet.
+	MaxUserDataPartSize = 16 * 1024
+	// MaxUserDataSize is the nova limit of the base64 encoded user data.
+	MaxUserDataSize = 65535
+
+	cloudConfigHeader    = "#cloud-config"
+	defaultShellShebang  = "#!/bin/bash"
+	userDataMergeType    = "list(append)+dict(no_replace,recurse_list)+str()"
+	userDataShellType    = "text/x-shellscript"
+	userDataCloudCfgType = "text/cloud-config"
+)
+
+// ValidateUserDataExtensions checks the size and the format of the user data
+// snippets of a cluster or a node group.
+func ValidateUserDataExtensions(extensions request.UserDataExtensions) error {
+	parts := map[string]string{
+		"preInstallScript":  extensions.PreInstallScript,
+		"postInstallScript": extensions.PostInstallScript,
+		"cloudConfig":       extensions.CloudConfig,
+	}
+	for name, part := range parts {
+		if len(part) > MaxUserDataPartSize {
+			return fmt.Errorf("%s exceeds the maximum size of %d bytes", name, MaxUserDataPartSize)
+		}
+		if !utf8.ValidString(part) || strings.ContainsRune(part, 0) {
+			return fmt.Errorf("%s must be valid UTF-8 text", name)
+		}
+	}
+
+	if extensions.CloudConfig != "" {
+		if !strings.HasPrefix(extensions.CloudConfig, cloudConfigHeader) {
+			return fmt.Errorf("cloudConfig must start with %s", cloudConfigHeader)
+		}
+		var cloudConfig map[string]interface{}
+		err := yaml.Unmarshal([]byte(extensions.CloudConfig), &cloudConfig)
+		if err != nil {
+			return fmt.Errorf("cloudConfig is not valid YAML: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// MergeUserData combines the generated init script with the user data
+// snippets into a multipart MIME document. The script is returned unchanged
+// when there are no snippets. Shell parts run in the order of their file
+// names, so pre install scripts run before and post install scripts run after
+// the init script.
+func MergeUserData(script string, extensions []request.UserDataExtensions) (string, error) {
+	type userDataPart struct {
+		contentType string
+		fileName    string
+		content     string
+	}
+
+	var cloudConfigs, preScripts, postScripts []userDataPart
+	for i, extension := range extensions {
+		if extension.CloudConfig != "" {
+			cloudConfigs = append(cloudConfigs, userDataPart{userDataCloudCfgType, fmt.Sprintf("%02d-cloud-config.yaml", i), extension.CloudConfig})
+		}
+		if extension.PreInstallScript != "" {
+			preScripts = append(preScripts, userDataPart{userDataShellType, fmt.Sprintf("00-pre-install-%02d.sh", i), withShebang(extension.PreInstallScript)})
+		}
+		if extension.PostInstallScript != "" {
+			postScripts = append(postScripts, userDataPart{userDataShellType, fmt.Sprintf("99-post-install-%02d.sh", i), withShebang(extension.PostInstallScript)})
+		}
+	}
+
+	if len(cloudConfigs) == 0 && len(preScripts) == 0 && len(postScripts) == 0 {
+		return script, nil
+	}
+
+	parts := append(cloudConfigs, preScripts...)
+	parts = append(parts, userDataPart{userDataShellType, "50-rke2-init.sh", script})
+	parts = append(parts, postScripts...)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range parts {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Type", fmt.Sprintf("%s; charset=\"utf-8\"", part.contentType))
+		header.Set("MIME-Version", "1.0")
+		header.Set("Content-Transfer-Encoding", "8bit")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", part.fileName))
+		if part.contentType == userDataCloudCfgType {
+			header.Set("Merge-Type", userDataMergeType)
+		}
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		_, err = partWriter.Write([]byte(part.content))
+		if err != nil {
+			return "", err
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		return "", err
+	}
+
+	userData := fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\nMIME-Version: 1.0\n\n%s", writer.Boundary(), body.String())
+	if len(base64.StdEncoding.EncodeToString([]byte(userData))) > MaxUserDataSize {
+		return "", fmt.Errorf("user data exceeds the maximum size of %d bytes", MaxUserDataSize)
+	}
+
+	return userData, nil
+}
+
+func withShebang(script string) string {
+	if strings.HasPrefix(script, "#!") {
+		return script
+	}
+	return defaultShellShebang + "\n" + script
+}
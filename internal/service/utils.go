@@ -6,12 +6,37 @@ import (
 	"encoding/json"
 	"math/rand"
 	"net/http"
+	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
+	"github.com/vmindtech/vke/internal/dto/request"
+	"github.com/vmindtech/vke/internal/dto/resource"
+	"github.com/vmindtech/vke/internal/model"
 	"gorm.io/datatypes"
 )
 
+// RKE2NetworkSettings are passed to the vke-agent, empty values keep the RKE2
+// defaults.
+type RKE2NetworkSettings struct {
+	ClusterCIDR   string
+	ServiceCIDR   string
+	ClusterDomain string
+	CNI           string
+	CNIOptions    resource.CNIOptions
+}
+
+func clusterRKE2NetworkSettings(cluster *model.Cluster) RKE2NetworkSettings {
+	return RKE2NetworkSettings{
+		ClusterCIDR:   cluster.ClusterPodCIDR,
+		ServiceCIDR:   cluster.ClusterServiceCIDR,
+		ClusterDomain: cluster.ClusterDomain,
+		CNI:           cluster.ClusterCNI,
+		CNIOptions:    getCNIOptions(cluster.ClusterCNIOptions),
+	}
+}
+
 func GenerateUserDataFromTemplate(
 	initiliazeFlag,
 	rke2AgentType,
@@ -33,6 +58,9 @@ func GenerateUserDataFromTemplate(
 	applicationCredentialKey,
 	clusterAgentVersion,
 	loadBalancerFloatingNetworkID string,
+	tlsSANs []string,
+	rke2Network RKE2NetworkSettings,
+	userDataExtensions []request.UserDataExtensions,
 ) (string, error) {
 	shFile := "scripts/rke2-init-sh.tpl"
 	t, err := template.ParseFiles(shFile)
@@ -40,6 +68,15 @@ func GenerateUserDataFromTemplate(
 		return "", err
 	}
 
+	cniKubeProxyReplacement := ""
+	if rke2Network.CNIOptions.KubeProxyReplacement {
+		cniKubeProxyReplacement = "true"
+	}
+	cniMTU := ""
+	if rke2Network.CNIOptions.MTU != 0 {
+		cniMTU = strconv.Itoa(rke2Network.CNIOptions.MTU)
+	}
+
 	var tpl bytes.Buffer
 
 	if err := t.Execute(&tpl, map[string]string{
@@ -63,11 +100,18 @@ func GenerateUserDataFromTemplate(
 		"clusterAgentVersion":           clusterAgentVersion,
 		"loadBalancerFloatingNetworkID": loadBalancerFloatingNetworkID,
 		"rke2NodeTaints":                rke2NodeTaints,
+		"tlsSan":                        strings.Join(append([]string{serverAddress}, tlsSANs...), ","),
+		"clusterCIDR":                   rke2Network.ClusterCIDR,
+		"serviceCIDR":                   rke2Network.ServiceCIDR,
+		"clusterDomain":                 rke2Network.ClusterDomain,
+		"cni":                           rke2Network.CNI,
+		"cniKubeProxyReplacement":       cniKubeProxyReplacement,
+		"cniMTU":                        cniMTU,
 	}); err != nil {
 		return "", err
 	}
 
-	return tpl.String(), nil
+	return MergeUserData(tpl.String(), userDataExtensions)
 }
 
 func Base64Encoder(data string) string {
@@ -7,6 +7,7 @@ const (
 	DeleteStateFloatingIP     = "FLOATING_IP"
 	DeleteStateNodes          = "NODES"
 	DeleteStateSecurityGroups = "SECURITY_GROUPS"
+	DeleteStateNetwork        = "NETWORK"
 	DeleteStateCredentials    = "CREDENTIALS"
 	DeleteStateCompleted      = "COMPLETED"
 )
@@ -25,6 +25,10 @@ const (
 	// Cluster Resource Errors
 	ErrLoadBalancerCreateFailed          = "Failed to create load balancer for cluster"
 	ErrLoadBalancerDeleteFailed          = "Failed to delete load balancer components"
+	ErrLoadBalancerSettingsInvalid       = "Invalid load balancer settings"
+	ErrLoadBalancerProviderInvalid       = "Invalid load balancer provider or flavor"
+	ErrDNSNameInvalid                    = "Invalid custom DNS name or TLS SAN"
+	ErrClusterCNIInvalid                 = "Invalid CNI plugin or options"
 	ErrDNSRecordCreateFailed             = "Failed to create DNS record for cluster"
 	ErrDNSRecordDeleteFailed             = "Failed to delete DNS record"
 	ErrFloatingIPCreateFailed            = "Failed to create floating IP for cluster"
@@ -41,17 +45,34 @@ const (
 	ErrNodeGroupScalingFailed = "Failed to scale node groups"
 
 	// Compute Errors
-	ErrComputeCreateFailed            = "Failed to create compute instance"
-	ErrComputeDeleteFailed            = "Failed to delete compute instance"
-	ErrComputeQuotaExceeded           = "Compute quota exceeded"
-	ErrComputeServerGroupCreateFailed = "Failed to create server group"
-	ErrComputeServerGroupDeleteFailed = "Failed to delete server group"
+	ErrComputeCreateFailed             = "Failed to create compute instance"
+	ErrComputeDeleteFailed             = "Failed to delete compute instance"
+	ErrComputeQuotaExceeded            = "Compute quota exceeded"
+	ErrComputeServerGroupCreateFailed  = "Failed to create server group"
+	ErrComputeServerGroupDeleteFailed  = "Failed to delete server group"
+	ErrComputeServerGroupPolicyInvalid = "Invalid server group policy"
+	ErrComputeAntiAffinityCapacity     = "Not enough compute hosts to satisfy the strict anti-affinity policy"
+	ErrComputePlacementTimeout         = "Timed out waiting for the servers of the server group to be scheduled"
+
+	// Image Errors
+	ErrImageValidationFailed = "Failed to validate image"
+	ErrImageNotFound         = "Image not found or not visible to the project"
+	ErrImageNotActive        = "Image is not active"
+	ErrImageNotApproved      = "Image is not in the approved image catalog"
+
+	// Master Replacement Errors
+	ErrMasterReplaceFailed    = "Failed to replace master node"
+	ErrEtcdMemberRemoveFailed = "Failed to remove etcd member of the replaced master"
+
+	// User Data Errors
+	ErrUserDataInvalid = "Invalid user data extensions"
 
 	// Network Errors
-	ErrNetworkCreateFailed = "Failed to create network components"
-	ErrNetworkDeleteFailed = "Failed to delete network components"
-	ErrSubnetCreateFailed  = "Failed to create subnet"
-	ErrSubnetDeleteFailed  = "Failed to delete subnet"
+	ErrNetworkCreateFailed   = "Failed to create network components"
+	ErrNetworkDeleteFailed   = "Failed to delete network components"
+	ErrSubnetCreateFailed    = "Failed to create subnet"
+	ErrSubnetDeleteFailed    = "Failed to delete subnet"
+	ErrClusterNetworkInvalid = "Invalid cluster pod, service or dns settings"
 
 	// Database Errors
 	ErrDatabaseConnectionFailed  = "Database connection failed"
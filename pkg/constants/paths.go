@@ -9,6 +9,8 @@ const (
 	FloatingIPPath  = "v2.0/floatingips"
 	OSInterfacePath = "os-interface"
 	TokenPath       = "v3/auth/tokens"
+	ImagePath       = "v2/images"
+	DNSZonesPath    = "v2/zones"
 )
 
 // Network related paths
@@ -17,9 +19,14 @@ const (
 	LoadBalancerPath       = "v2/lbaas/loadbalancers"
 	ListenersPath          = "v2/lbaas/listeners"
 	SubnetsPath            = "v2.0/subnets"
+	NetworksPath           = "v2.0/networks"
+	RoutersPath            = "v2.0/routers"
 	NetworkPort            = "v2.0/ports"
 	SecurityGroupPath      = "v2.0/security-groups"
 	SecurityGroupRulesPath = "v2.0/security-group-rules"
 	HealthMonitorPath      = "v2/lbaas/healthmonitors"
 	ListenerPoolPath       = "v2/lbaas/pools"
+	LBProvidersPath        = "v2/lbaas/providers"
+	LBFlavorsPath          = "v2/lbaas/flavors"
+	LBFlavorProfilesPath   = "v2/lbaas/flavorprofiles"
 )
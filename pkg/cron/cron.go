@@ -0,0 +1,165 @@
+// Package cron parses standard five field cron expressions
+// (minute, hour, day of month, month, day of week).
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLookahead bounds the search for the next activation, expressions like
+// "0 0 30 2 *" never match and would otherwise loop forever.
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+type field struct {
+	min, max int
+}
+
+var (
+	minuteField     = field{0, 59}
+	hourField       = field{0, 23}
+	dayOfMonthField = field{1, 31}
+	monthField      = field{1, 12}
+	dayOfWeekField  = field{0, 7}
+)
+
+type Schedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	// As in Vixie cron day of month and day of week are ORed when both are
+	// restricted and ANDed when either starts with "*", so "*/2" still
+	// restricts its field.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// Parse parses a five field cron expression. Each field accepts "*", single
+// values, ranges ("1-5"), steps ("*/15", "0-30/10") and comma separated lists.
+// In the day of week field both 0 and 7 are Sunday.
+func Parse(expr string) (*Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q, expected 5 fields", expr)
+	}
+
+	s := &Schedule{
+		anyDayOfMonth: strings.HasPrefix(fields[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(fields[4], "*"),
+	}
+
+	var err error
+	if s.minutes, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.daysOfMonth, err = parseField(fields[2], dayOfMonthField); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.daysOfWeek, err = parseField(fields[4], dayOfWeekField); err != nil {
+		return nil, err
+	}
+	if s.daysOfWeek[7] {
+		s.daysOfWeek[0] = true
+	}
+
+	return s, nil
+}
+
+func parseField(value string, f field) (map[int]bool, error) {
+	values := map[int]bool{}
+
+	for _, part := range strings.Split(value, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in cron field %q", value)
+			}
+			part = part[:idx]
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid range in cron field %q", value)
+			}
+			if end, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid range in cron field %q", value)
+			}
+		default:
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value in cron field %q", value)
+			}
+			start = v
+			if step == 1 {
+				end = v
+			}
+		}
+
+		if start < f.min || end > f.max || start > end {
+			return nil, fmt.Errorf("cron field %q is out of range %d-%d", value, f.min, f.max)
+		}
+
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+// Next returns the first activation time strictly after t, in the location of
+// t. It returns the zero time when the expression never matches.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxLookahead)
+
+	for t.Before(limit) {
+		if !s.months[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hours[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minutes[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) matchDay(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth[t.Day()]
+	dayOfWeek := s.daysOfWeek[int(t.Weekday())]
+
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
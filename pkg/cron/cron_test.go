@@ -0,0 +1,87 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// 2026-10-18 is a Sunday.
+	from := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want []time.Time
+	}{
+		{
+			name: "every day",
+			expr: "0 8 * * *",
+			want: []time.Time{
+				time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "stepped day of month",
+			expr: "0 8 */2 * *",
+			want: []time.Time{
+				time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 21, 8, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 23, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "stepped day of week",
+			expr: "0 0 * * */2",
+			want: []time.Time{
+				time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 22, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 24, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "stepped day of month and restricted day of week",
+			expr: "0 0 */2 * 1",
+			want: []time.Time{
+				time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "restricted day of month and stepped day of week",
+			expr: "0 0 1 * */2",
+			want: []time.Time{
+				time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "restricted day of month or day of week",
+			expr: "0 0 20 * 1",
+			want: []time.Time{
+				time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.expr, err)
+			}
+			next := from
+			for _, want := range tt.want {
+				next = s.Next(next)
+				if !next.Equal(want) {
+					t.Fatalf("Next() = %v, want %v", next, want)
+				}
+			}
+		})
+	}
+}
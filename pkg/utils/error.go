@@ -25,7 +25,25 @@ const (
 	FailedToGetInstancesMsg      = "failed to get instances."
 	FailedToGetNodeGroupsMsg     = "failed to get node groups."
 	FailedToGetClusterFlavorMsg  = "failed to get cluster flavor."
+	FailedToUpdateNodeGroupMsg   = "failed to update node group."
 	FailedToDeleteNodeGroupMsg   = "failed to delete node group."
+	FailedToGetDeleteImpactMsg   = "failed to get node group delete impact."
+	FailedToCloneNodeGroupMsg    = "failed to clone node group."
+	FailedToGetImageCatalogMsg   = "failed to get image catalog."
+	FailedToReplaceMasterMsg     = "failed to replace master node."
+	FailedToGetDriftMsg          = "failed to get control plane drift."
+	FailedToReconcileMsg         = "failed to reconcile control plane."
+
+	FailedToCreateNodeGroupScheduleMsg = "failed to create node group schedule."
+	FailedToGetNodeGroupSchedulesMsg   = "failed to get node group schedules."
+	FailedToDeleteNodeGroupScheduleMsg = "failed to delete node group schedule."
+
+	FailedToCreateIngressLoadBalancerMsg = "failed to create ingress load balancer."
+	FailedToGetIngressLoadBalancerMsg    = "failed to get ingress load balancer."
+	FailedToDeleteIngressLoadBalancerMsg = "failed to delete ingress load balancer."
+
+	FailedToGetDNSOrphansMsg    = "failed to get orphan dns records."
+	FailedToDeleteDNSOrphansMsg = "failed to delete orphan dns records."
 )
 
 type ErrorBag struct {
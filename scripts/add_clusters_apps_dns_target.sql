@@ -0,0 +1,10 @@
+-- Add apps wildcard DNS target to clusters table
+-- This migration adds the column storing where the *.apps wildcard record of a cluster points
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_apps_dns_target` varchar(64) DEFAULT NULL 
+AFTER `cluster_tls_sans`;
+
+-- Add comment to column
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_apps_dns_target` varchar(64) DEFAULT NULL COMMENT 'Target of the apps wildcard record, ingress or an IP address';
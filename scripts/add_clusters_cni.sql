@@ -0,0 +1,13 @@
+-- Add CNI plugin and options to clusters table
+-- This migration adds the columns storing the CNI selected at cluster creation
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_cni` varchar(32) DEFAULT NULL 
+AFTER `cluster_domain`,
+ADD COLUMN `cluster_cni_options` json DEFAULT NULL 
+AFTER `cluster_cni`;
+
+-- Add comments to columns
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_cni` varchar(32) DEFAULT NULL COMMENT 'CNI plugin (canal, calico, cilium or none), empty for the vke-agent default',
+MODIFY COLUMN `cluster_cni_options` json DEFAULT NULL COMMENT 'CNI options such as the cilium kube-proxy replacement and the MTU';
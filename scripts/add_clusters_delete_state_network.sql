@@ -0,0 +1,5 @@
+-- Add network delete state to clusters table
+-- This migration adds the stage removing the networks, subnets and routers vke created for a cluster
+
+ALTER TABLE `clusters` 
+MODIFY COLUMN `delete_state` enum('initial', 'loadbalancer', 'dns', 'floating_ip', 'nodes', 'security_groups', 'network', 'credentials', 'completed') DEFAULT 'initial';
@@ -0,0 +1,13 @@
+-- Add custom DNS records and TLS SANs to clusters table
+-- This migration adds the columns storing the custom DNS records and the extra TLS SANs of the cluster API endpoint
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_dns_records` json DEFAULT NULL 
+AFTER `cluster_dns_record_id`,
+ADD COLUMN `cluster_tls_sans` json DEFAULT NULL 
+AFTER `cluster_dns_records`;
+
+-- Add comment to columns
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_dns_records` json DEFAULT NULL COMMENT 'DNS records of the cluster next to the API endpoint record',
+MODIFY COLUMN `cluster_tls_sans` json DEFAULT NULL COMMENT 'Additional TLS SANs of the API server certificate';
@@ -0,0 +1,15 @@
+-- Replace the cloudflare record id of clusters table with a generic DNS provider and record reference
+-- This migration renames cluster_cloudflare_record_id and stores the provider which created the record
+
+ALTER TABLE `clusters` 
+CHANGE COLUMN `cluster_cloudflare_record_id` `cluster_dns_record_id` varchar(255) DEFAULT NULL,
+ADD COLUMN `cluster_dns_provider` varchar(32) DEFAULT NULL 
+AFTER `floating_ip_uuid`;
+
+-- Existing records were created in cloudflare
+UPDATE `clusters` SET `cluster_dns_provider` = 'cloudflare' WHERE `cluster_dns_record_id` IS NOT NULL;
+
+-- Add comment to columns
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_dns_provider` varchar(32) DEFAULT NULL COMMENT 'DNS provider of the cluster endpoint record (cloudflare, designate, rfc2136)',
+MODIFY COLUMN `cluster_dns_record_id` varchar(255) DEFAULT NULL COMMENT 'Provider specific reference of the cluster endpoint record';
@@ -0,0 +1,10 @@
+-- Add cluster DNS domain to clusters table
+-- This migration adds the column storing the cluster DNS domain passed to RKE2
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_domain` varchar(253) DEFAULT NULL 
+AFTER `cluster_service_cidr`;
+
+-- Add comments to columns
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_domain` varchar(253) DEFAULT NULL COMMENT 'Cluster DNS domain, empty for the RKE2 default';
@@ -0,0 +1,13 @@
+-- Add load balancer provider and flavor to clusters table
+-- This migration adds the columns storing the Octavia provider and flavor used by the load balancers of a cluster
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_load_balancer_provider` varchar(64) DEFAULT NULL 
+AFTER `cluster_load_balancer_settings`,
+ADD COLUMN `cluster_load_balancer_flavor_id` varchar(36) DEFAULT NULL 
+AFTER `cluster_load_balancer_provider`;
+
+-- Add comment to columns
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_load_balancer_provider` varchar(64) DEFAULT NULL COMMENT 'Octavia provider of the load balancers of the cluster',
+MODIFY COLUMN `cluster_load_balancer_flavor_id` varchar(36) DEFAULT NULL COMMENT 'Octavia flavor of the load balancers of the cluster';
@@ -0,0 +1,10 @@
+-- Add load balancer settings to clusters table
+-- This migration adds the column storing the health monitor and pool settings of the control plane load balancer
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_load_balancer_settings` json DEFAULT NULL 
+AFTER `cluster_user_data`;
+
+-- Add comment to column
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_load_balancer_settings` json DEFAULT NULL COMMENT 'Health monitor and pool settings of the control plane load balancer';
@@ -0,0 +1,13 @@
+-- Add pod and service CIDRs to clusters table
+-- This migration adds the columns storing the pod and service networks passed to RKE2
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_pod_cidr` varchar(128) DEFAULT NULL 
+AFTER `cluster_apps_dns_target`,
+ADD COLUMN `cluster_service_cidr` varchar(128) DEFAULT NULL 
+AFTER `cluster_pod_cidr`;
+
+-- Add comments to columns
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_pod_cidr` varchar(128) DEFAULT NULL COMMENT 'Comma separated pod CIDRs, empty for the RKE2 default',
+MODIFY COLUMN `cluster_service_cidr` varchar(128) DEFAULT NULL COMMENT 'Comma separated service CIDRs, empty for the RKE2 default';
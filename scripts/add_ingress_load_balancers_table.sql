@@ -0,0 +1,20 @@
+-- Add ingress_load_balancers table for managed ingress load balancers
+-- This migration adds the table holding the Octavia load balancer that exposes the ingress of a cluster
+
+CREATE TABLE IF NOT EXISTS `ingress_load_balancers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `cluster_uuid` varchar(36) DEFAULT NULL,
+  `node_group_uuid` varchar(36) DEFAULT NULL,
+  `load_balancer_uuid` varchar(36) DEFAULT NULL,
+  `vip_address` varchar(64) DEFAULT NULL,
+  `floating_ip_uuid` varchar(36) DEFAULT NULL,
+  `floating_ip` varchar(64) DEFAULT NULL,
+  `listeners` json DEFAULT NULL,
+  `create_date` datetime DEFAULT NULL,
+  `update_date` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `cluster_uuid` (`cluster_uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Add comment to table
+ALTER TABLE `ingress_load_balancers` COMMENT = 'Stores the ingress load balancers of clusters';
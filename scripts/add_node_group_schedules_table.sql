@@ -0,0 +1,24 @@
+-- Add node_group_schedules table for scheduled node group scaling
+-- This migration adds the table holding cron based scaling schedules of node groups
+
+CREATE TABLE IF NOT EXISTS `node_group_schedules` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `schedule_uuid` varchar(36) DEFAULT NULL,
+  `cluster_uuid` varchar(36) DEFAULT NULL,
+  `node_group_uuid` varchar(36) DEFAULT NULL,
+  `cron_expression` varchar(100) NOT NULL,
+  `timezone` varchar(64) NOT NULL DEFAULT 'UTC',
+  `min_size` int DEFAULT NULL,
+  `max_size` int DEFAULT NULL,
+  `desired_size` int DEFAULT NULL,
+  `enabled` tinyint(1) NOT NULL DEFAULT 1,
+  `last_run_date` datetime DEFAULT NULL,
+  `create_date` datetime DEFAULT NULL,
+  `update_date` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `schedule_uuid` (`schedule_uuid`),
+  KEY `idx_node_group_uuid` (`node_group_uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Add comment to table
+ALTER TABLE `node_group_schedules` COMMENT = 'Stores cron based scaling schedules of node groups';
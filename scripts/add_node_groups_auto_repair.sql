@@ -0,0 +1,10 @@
+-- Add auto repair support to node_groups table
+-- This migration adds the node_group_auto_repair column to enable automatic replacement of unhealthy nodes
+
+ALTER TABLE `node_groups` 
+ADD COLUMN `node_group_auto_repair` tinyint(1) NOT NULL DEFAULT 0 
+AFTER `node_group_security_group`;
+
+-- Add comment to column
+ALTER TABLE `node_groups` 
+MODIFY COLUMN `node_group_auto_repair` tinyint(1) NOT NULL DEFAULT 0 COMMENT 'Replace unhealthy nodes of the node group automatically';
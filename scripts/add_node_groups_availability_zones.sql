@@ -0,0 +1,10 @@
+-- Add availability zone support to node_groups table
+-- This migration adds the node_group_availability_zones column to store the availability zones the nodes are spread over
+
+ALTER TABLE `node_groups` 
+ADD COLUMN `node_group_availability_zones` json DEFAULT NULL 
+AFTER `node_group_networks`;
+
+-- Add comment to column
+ALTER TABLE `node_groups` 
+MODIFY COLUMN `node_group_availability_zones` json DEFAULT NULL COMMENT 'Availability zones of the nodes, empty uses the default zone';
@@ -0,0 +1,10 @@
+-- Add data volume support to node_groups table
+-- This migration adds the node_group_data_volumes column to store the extra volumes attached to the nodes
+
+ALTER TABLE `node_groups` 
+ADD COLUMN `node_group_data_volumes` json DEFAULT NULL 
+AFTER `node_image_ref`;
+
+-- Add comment to column
+ALTER TABLE `node_groups` 
+MODIFY COLUMN `node_group_data_volumes` json DEFAULT NULL COMMENT 'Extra data volumes with size, volume type and delete on termination flag';
@@ -0,0 +1,10 @@
+-- Add image support to node_groups table
+-- This migration adds the node_image_ref column to store the Glance image of the node group
+
+ALTER TABLE `node_groups` 
+ADD COLUMN `node_image_ref` varchar(36) DEFAULT NULL 
+AFTER `server_group_policy`;
+
+-- Add comment to column
+ALTER TABLE `node_groups` 
+MODIFY COLUMN `node_image_ref` varchar(36) DEFAULT NULL COMMENT 'Glance image UUID of the node group, NULL uses the global IMAGE_REF';
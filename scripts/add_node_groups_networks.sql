@@ -0,0 +1,10 @@
+-- Add additional network support to node_groups table
+-- This migration adds the node_group_networks column to store the additional subnets and security groups of the nodes
+
+ALTER TABLE `node_groups` 
+ADD COLUMN `node_group_networks` json DEFAULT NULL 
+AFTER `node_group_user_data`;
+
+-- Add comment to column
+ALTER TABLE `node_groups` 
+MODIFY COLUMN `node_group_networks` json DEFAULT NULL COMMENT 'Additional networks with subnet id and security group ids';
@@ -0,0 +1,13 @@
+-- Add server group policy support to node_groups table
+-- This migration adds the server_group_policy column to store the affinity policy of the node group server group
+
+ALTER TABLE `node_groups` 
+ADD COLUMN `server_group_policy` varchar(20) DEFAULT NULL 
+AFTER `node_group_auto_repair`;
+
+-- Existing server groups were created with the soft-anti-affinity policy
+UPDATE `node_groups` SET `server_group_policy` = 'soft-anti-affinity' WHERE `server_group_policy` IS NULL;
+
+-- Add comment to column
+ALTER TABLE `node_groups` 
+MODIFY COLUMN `server_group_policy` varchar(20) DEFAULT NULL COMMENT 'Nova server group policy: affinity, anti-affinity, soft-affinity, soft-anti-affinity';
@@ -0,0 +1,17 @@
+-- Add user data extension support to clusters and node_groups tables
+-- This migration adds the columns storing the pre install, post install and cloud-config snippets
+
+ALTER TABLE `clusters` 
+ADD COLUMN `cluster_user_data` json DEFAULT NULL 
+AFTER `application_credential_id`;
+
+ALTER TABLE `node_groups` 
+ADD COLUMN `node_group_user_data` json DEFAULT NULL 
+AFTER `node_group_data_volumes`;
+
+-- Add comment to columns
+ALTER TABLE `clusters` 
+MODIFY COLUMN `cluster_user_data` json DEFAULT NULL COMMENT 'User data snippets applied to all nodes of the cluster';
+
+ALTER TABLE `node_groups` 
+MODIFY COLUMN `node_group_user_data` json DEFAULT NULL COMMENT 'User data snippets applied to the nodes of the node group';
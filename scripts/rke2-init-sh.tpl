@@ -4,4 +4,4 @@ systemctl stop ufw
 systemctl disable ufw
 tar -xvf vke-agent_v{{.vkeAgentVersion}}_linux_amd64.tar.gz
 chmod +x vke-agent
-./vke-agent --initialize={{.initiliazeFlag}} --rke2AgentType={{.rke2AgentType}} --rke2Token={{.rke2Token}} --serverAddress={{.serverAddress}} --kubeversion={{.kubeVersion}} --tlsSan={{.serverAddress}} --rke2ClusterName={{.clusterName}} --rke2ClusterUUID={{.clusterUUID}} --rke2ClusterProjectUUID={{.projectUUID}} --rke2AgentVKEAPIEndpoint={{.vkeAPIEndpoint}} --rke2AgentVKEAPIAuthToken={{.authToken}} --rke2NodeLabel={{.rke2NodeLabel}} --rke2NodeTaints={{.rke2NodeTaints}} --vkeCloudAuthURL={{.vkeCloudAuthURL}} --clusterAutoscalerVersion={{.clusterAutoscalerVersion}} --clusterAgentVersion={{.clusterAgentVersion}} --cloudProviderVkeVersion={{.cloudProviderVkeVersion}} --applicationCredentialID={{.applicationCredentialID}} --applicationCredentialKey={{.applicationCredentialKey}} --loadBalancerFloatingNetworkID={{.loadBalancerFloatingNetworkID}}
\ No newline at end of file
+./vke-agent --initialize={{.initiliazeFlag}} --rke2AgentType={{.rke2AgentType}} --rke2Token={{.rke2Token}} --serverAddress={{.serverAddress}} --kubeversion={{.kubeVersion}} --tlsSan={{.tlsSan}} --rke2ClusterName={{.clusterName}} --rke2ClusterUUID={{.clusterUUID}} --rke2ClusterProjectUUID={{.projectUUID}} --rke2AgentVKEAPIEndpoint={{.vkeAPIEndpoint}} --rke2AgentVKEAPIAuthToken={{.authToken}} --rke2NodeLabel={{.rke2NodeLabel}} --rke2NodeTaints={{.rke2NodeTaints}} --vkeCloudAuthURL={{.vkeCloudAuthURL}} --clusterAutoscalerVersion={{.clusterAutoscalerVersion}} --clusterAgentVersion={{.clusterAgentVersion}} --cloudProviderVkeVersion={{.cloudProviderVkeVersion}} --applicationCredentialID={{.applicationCredentialID}} --applicationCredentialKey={{.applicationCredentialKey}} --loadBalancerFloatingNetworkID={{.loadBalancerFloatingNetworkID}}{{if .clusterCIDR}} --rke2ClusterCIDR={{.clusterCIDR}}{{end}}{{if .serviceCIDR}} --rke2ServiceCIDR={{.serviceCIDR}}{{end}}{{if .clusterDomain}} --rke2ClusterDomain={{.clusterDomain}}{{end}}{{if .cni}} --rke2CNI={{.cni}}{{end}}{{if .cniKubeProxyReplacement}} --rke2CNIKubeProxyReplacement={{.cniKubeProxyReplacement}}{{end}}{{if .cniMTU}} --rke2CNIMTU={{.cniMTU}}{{end}}
\ No newline at end of file
@@ -57,10 +57,23 @@ CREATE TABLE `clusters` (
   `cluster_api_access` enum('public','private') DEFAULT 'public',
   `cluster_agent_token` varchar(255) DEFAULT NULL,
   `floating_ip_uuid` varchar(255) DEFAULT NULL,
-  `cluster_cloudflare_record_id` varchar(36) DEFAULT NULL,
+  `cluster_dns_provider` varchar(32) DEFAULT NULL,
+  `cluster_dns_record_id` varchar(255) DEFAULT NULL,
+  `cluster_dns_records` json DEFAULT NULL,
+  `cluster_tls_sans` json DEFAULT NULL,
+  `cluster_apps_dns_target` varchar(64) DEFAULT NULL,
+  `cluster_pod_cidr` varchar(128) DEFAULT NULL,
+  `cluster_service_cidr` varchar(128) DEFAULT NULL,
+  `cluster_domain` varchar(253) DEFAULT NULL,
+  `cluster_cni` varchar(32) DEFAULT NULL,
+  `cluster_cni_options` json DEFAULT NULL,
   `cluster_shared_security_group` varchar(50) DEFAULT NULL,
   `application_credential_id` varchar(36) DEFAULT NULL,
-  `delete_state` enum('initial', 'loadbalancer', 'dns', 'floating_ip', 'nodes', 'security_groups', 'credentials', 'completed') DEFAULT 'initial',
+  `cluster_user_data` json DEFAULT NULL,
+  `cluster_load_balancer_settings` json DEFAULT NULL,
+  `cluster_load_balancer_provider` varchar(64) DEFAULT NULL,
+  `cluster_load_balancer_flavor_id` varchar(36) DEFAULT NULL,
+  `delete_state` enum('initial', 'loadbalancer', 'dns', 'floating_ip', 'nodes', 'security_groups', 'network', 'credentials', 'completed') DEFAULT 'initial',
   `cluster_certificate_expire_date` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
   UNIQUE KEY `cluster_uuid` (`cluster_uuid`)
@@ -104,6 +117,56 @@ CREATE TABLE `kubeconfigs` (
 ) ENGINE=InnoDB AUTO_INCREMENT=26 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `ingress_load_balancers`
+--
+
+DROP TABLE IF EXISTS `ingress_load_balancers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `ingress_load_balancers` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `cluster_uuid` varchar(36) DEFAULT NULL,
+  `node_group_uuid` varchar(36) DEFAULT NULL,
+  `load_balancer_uuid` varchar(36) DEFAULT NULL,
+  `vip_address` varchar(64) DEFAULT NULL,
+  `floating_ip_uuid` varchar(36) DEFAULT NULL,
+  `floating_ip` varchar(64) DEFAULT NULL,
+  `listeners` json DEFAULT NULL,
+  `create_date` datetime DEFAULT NULL,
+  `update_date` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `cluster_uuid` (`cluster_uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `node_group_schedules`
+--
+
+DROP TABLE IF EXISTS `node_group_schedules`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `node_group_schedules` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `schedule_uuid` varchar(36) DEFAULT NULL,
+  `cluster_uuid` varchar(36) DEFAULT NULL,
+  `node_group_uuid` varchar(36) DEFAULT NULL,
+  `cron_expression` varchar(100) NOT NULL,
+  `timezone` varchar(64) NOT NULL DEFAULT 'UTC',
+  `min_size` int DEFAULT NULL,
+  `max_size` int DEFAULT NULL,
+  `desired_size` int DEFAULT NULL,
+  `enabled` tinyint(1) NOT NULL DEFAULT 1,
+  `last_run_date` datetime DEFAULT NULL,
+  `create_date` datetime DEFAULT NULL,
+  `update_date` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `schedule_uuid` (`schedule_uuid`),
+  KEY `idx_node_group_uuid` (`node_group_uuid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `node_groups`
 --
@@ -125,6 +188,13 @@ CREATE TABLE `node_groups` (
   `node_groups_status` enum('Active','Updating','Deleted','Creating') DEFAULT NULL,
   `node_groups_type` enum('master','worker') DEFAULT NULL,
   `node_group_security_group` varchar(50) DEFAULT NULL,
+  `node_group_auto_repair` tinyint(1) NOT NULL DEFAULT 0,
+  `server_group_policy` varchar(20) DEFAULT NULL,
+  `node_image_ref` varchar(36) DEFAULT NULL,
+  `node_group_data_volumes` json DEFAULT NULL,
+  `node_group_user_data` json DEFAULT NULL,
+  `node_group_networks` json DEFAULT NULL,
+  `node_group_availability_zones` json DEFAULT NULL,
   `is_hidden` tinyint(1) DEFAULT NULL,
   `node_group_create_date` datetime DEFAULT NULL,
   `node_group_update_date` datetime DEFAULT NULL,